@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -15,12 +16,18 @@ type AtlassianConfig struct {
 	CallbackUrl  string
 }
 
+type GitLabConfig struct {
+	BaseURL      string   // default instance for users that do not set their own
+	AllowedHosts []string // instances users may connect to besides the default, any public host when empty
+}
+
 type Config struct {
 	APIVersion      string
 	MongoURI        string
 	Port            string
 	JWTSecret       string
 	AtlassianConfig AtlassianConfig
+	GitLabConfig    GitLabConfig
 }
 
 var AppConfig *Config
@@ -46,6 +53,10 @@ func LoadConfig() *Config {
 			Scope:        os.Getenv("ATLASSIAN_SCOPE"),
 			CallbackUrl:  os.Getenv("ATLASSIAN_CALLBACK_URL"),
 		},
+		GitLabConfig: GitLabConfig{
+			BaseURL:      os.Getenv("GITLAB_URL"),
+			AllowedHosts: splitList(os.Getenv("GITLAB_ALLOWED_HOSTS")),
+		},
 	}
 	AppConfig = cfg
 	return cfg
@@ -77,4 +88,19 @@ func CheckRequiredVariables(cfg *Config) {
 	if cfg.AtlassianConfig.CallbackUrl == "" {
 		log.Fatal("ATLASSIAN_CALLBACK_URL environment variable is not defined")
 	}
+	if cfg.GitLabConfig.BaseURL == "" {
+		log.Println("GITLAB_URL environment variable is not defined, using https://gitlab.com")
+		cfg.GitLabConfig.BaseURL = "https://gitlab.com"
+	}
+}
+
+// splitList splits a comma separated environment variable, ignoring blanks.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
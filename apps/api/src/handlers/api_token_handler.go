@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type APITokenHandler struct {
+	service *services.APITokenService
+}
+
+func NewAPITokenHandler(s *services.APITokenService) *APITokenHandler {
+	return &APITokenHandler{service: s}
+}
+
+func (h *APITokenHandler) Create(c *gin.Context) {
+	var input dtos.CreateAPITokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	token, err := h.service.CreateToken(c, c.GetString("user_id"), input.Name, input.Scope)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating token"})
+		return
+	}
+	c.JSON(http.StatusCreated, token)
+}
+
+func (h *APITokenHandler) List(c *gin.Context) {
+	tokens, err := h.service.ListTokens(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching tokens"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *APITokenHandler) Revoke(c *gin.Context) {
+	err := h.service.RevokeToken(c, c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Token not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking token"})
+		return
+	}
+	c.Status(http.StatusOK)
+}
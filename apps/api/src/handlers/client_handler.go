@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientHandler struct {
+	service     *services.ClientService
+	permissions *services.PermissionService
+}
+
+func NewClientHandler(s *services.ClientService, ps *services.PermissionService) *ClientHandler {
+	return &ClientHandler{service: s, permissions: ps}
+}
+
+func (h *ClientHandler) Create(c *gin.Context) {
+	var input dtos.CreateClientInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	client := models.Client{
+		Name:        input.Name,
+		OwnerID:     c.GetString("user_id"),
+		WorkspaceID: c.GetString("workspace_id"),
+	}
+	if !authorize(c, h.permissions, client.WorkspaceID, services.PermManageBilling) {
+		return
+	}
+
+	if err := h.service.CreateClient(c, &client); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create client"})
+		return
+	}
+	c.JSON(http.StatusOK, client)
+}
+
+// List returns the clients of the active workspace, or the personal clients.
+func (h *ClientHandler) List(c *gin.Context) {
+	clients, err := h.service.GetClients(c, c.GetString("user_id"), c.GetString("workspace_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		return
+	}
+	c.JSON(http.StatusOK, clients)
+}
+
+func (h *ClientHandler) Update(c *gin.Context) {
+	var input dtos.UpdateClientInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	workspaceID := c.GetString("workspace_id")
+	client, err := h.service.GetClient(c, c.Param("id"), c.GetString("user_id"), workspaceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+	if !authorize(c, h.permissions, workspaceID, services.PermManageBilling) {
+		return
+	}
+
+	if input.Name != nil {
+		if err := h.service.RenameClient(c, client, *input.Name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, client)
+}
+
+func (h *ClientHandler) Delete(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	client, err := h.service.GetClient(c, c.Param("id"), c.GetString("user_id"), workspaceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+	if !authorize(c, h.permissions, workspaceID, services.PermManageBilling) {
+		return
+	}
+
+	if err := h.service.DeleteClient(c, client.ID); err != nil {
+		if errors.Is(err, services.ErrClientInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Delete failed", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		return
+	}
+	c.Status(http.StatusOK)
+}
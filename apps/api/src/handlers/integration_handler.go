@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IntegrationHandler struct {
+	registry         *services.IntegrationRegistry
+	atlassianService *services.AtlassianService
+	gitLabService    *services.GitLabService
+	timeEntryService *services.TimeEntryService
+	projectService   *services.ProjectService
+	permissions      *services.PermissionService
+}
+
+func NewIntegrationHandler(r *services.IntegrationRegistry, as *services.AtlassianService, gs *services.GitLabService, tes *services.TimeEntryService, prs *services.ProjectService, ps *services.PermissionService) *IntegrationHandler {
+	return &IntegrationHandler{registry: r, atlassianService: as, gitLabService: gs, timeEntryService: tes, projectService: prs, permissions: ps}
+}
+
+func (h *IntegrationHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"integrations": h.registry.Types()})
+}
+
+func (h *IntegrationHandler) SearchJira(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing search query"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit, must be between 1 and 50"})
+		return
+	}
+
+	issues, err := h.atlassianService.SearchJiraIssues(c.GetString("user_id"), query, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrAtlassianReauthRequired) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Atlassian re-authentication required"})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Jira search failed", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, issues)
+}
+
+// maxWorklogRange limits how much time a single reconciliation or import covers.
+const maxWorklogRange = 366 * 24 * time.Hour
+
+func (h *IntegrationHandler) ReconcileJira(c *gin.Context) {
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, must be RFC 3339"})
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.Query("from"); fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, must be RFC 3339"})
+			return
+		}
+		from = t
+	}
+	if !from.Before(to) || to.Sub(from) > maxWorklogRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid range, from must be before to and at most a year apart"})
+		return
+	}
+
+	report, err := h.timeEntryService.ReconcileWorklogs(c, c.GetString("user_id"), "jira", from, to)
+	if err != nil {
+		respondIntegrationError(c, "Reconciliation failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *IntegrationHandler) ResolveJiraDrift(c *gin.Context) {
+	var input dtos.ResolveDriftInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	// Resolving changes the entries or logs time in Jira, in the workspace of
+	// the project of each item. An orphaned worklog that is pulled becomes an
+	// entry of the project given with it.
+	projectIDs := make([]string, 0, len(input.Items))
+	for _, item := range input.Items {
+		if item.Kind == models.DriftOrphaned {
+			projectIDs = append(projectIDs, item.ProjectID)
+		} else if entry, err := h.timeEntryService.GetTimeEntryByID(c, item.TimeEntryID, c.GetString("user_id")); err == nil {
+			projectIDs = append(projectIDs, entry.ProjectID)
+		}
+	}
+	if !authorizeProjects(c, h.permissions, h.projectService, projectIDs, services.PermLogTime) {
+		return
+	}
+
+	results, err := h.timeEntryService.ResolveDrift(c, c.GetString("user_id"), "jira", input.Items)
+	if err != nil {
+		respondIntegrationError(c, "Resolving differences failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (h *IntegrationHandler) ImportJiraWorklogs(c *gin.Context) {
+	var input dtos.ImportWorklogsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if !input.From.Before(input.To) || input.To.Sub(input.From) > maxWorklogRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid range, from must be before to and at most a year apart"})
+		return
+	}
+
+	// Importing logs time and creates projects for issues without one.
+	workspaceID := c.GetString("workspace_id")
+	if !authorize(c, h.permissions, workspaceID, services.PermLogTime) ||
+		!authorize(c, h.permissions, workspaceID, services.PermCreateProject) {
+		return
+	}
+
+	result, err := h.timeEntryService.ImportWorklogs(c, c.GetString("user_id"), workspaceID, "jira", input.From, input.To, input.DryRun)
+	if err != nil {
+		respondIntegrationError(c, "Import failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func respondIntegrationError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrAtlassianReauthRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Atlassian re-authentication required"})
+	case errors.Is(err, services.ErrUnknownIntegration), errors.Is(err, services.ErrReconcileUnsupported), errors.Is(err, services.ErrImportUnsupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": message, "details": err.Error()})
+	}
+}
+
+func (h *IntegrationHandler) ConnectGitLab(c *gin.Context) {
+	var input dtos.ConnectGitLabInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.gitLabService.Connect(c.GetString("user_id"), &input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not connect to GitLab", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *IntegrationHandler) DisconnectGitLab(c *gin.Context) {
+	if err := h.gitLabService.Disconnect(c.GetString("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not disconnect GitLab"})
+		return
+	}
+	c.Status(http.StatusOK)
+}
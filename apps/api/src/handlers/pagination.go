@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultPageLimit = 20
+
+// pageParams reads the cursor, skip and limit query parameters of a list.
+// Missing or invalid values start at the first item and return 20 of them.
+func pageParams(c *gin.Context) (cursor string, skip, limit int64) {
+	skip, _ = strconv.ParseInt(c.Query("skip"), 10, 64)
+	if skip < 0 {
+		skip = 0
+	}
+	limit, _ = strconv.ParseInt(c.Query("limit"), 10, 64)
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	return c.Query("cursor"), skip, limit
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authorize checks that the user of the request has the permission in the
+// workspace and responds with 403 Forbidden when not. It reports whether the
+// handler may continue. An empty workspaceID stands for the user's own data.
+func authorize(c *gin.Context, ps *services.PermissionService, workspaceID string, permission services.Permission) bool {
+	resource := c.Request.Method + " " + c.FullPath()
+	err := ps.Authorize(c, c.GetString("user_id"), workspaceID, permission, resource)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, services.ErrPermissionDenied) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied", "details": err.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking permissions"})
+	}
+	return false
+}
+
+// authorizeProjects checks the permission in the workspace of each project,
+// once per workspace, like authorize. Projects that cannot be found are left to
+// the service, which reports them per item.
+func authorizeProjects(c *gin.Context, ps *services.PermissionService, projectService *services.ProjectService, projectIDs []string, permission services.Permission) bool {
+	seen := make(map[string]bool)
+	checked := make(map[string]bool)
+	for _, id := range projectIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		project, err := projectService.GetProjectByID(c, id, c.GetString("user_id"))
+		if err != nil || checked[project.WorkspaceID] {
+			continue
+		}
+		if !authorize(c, ps, project.WorkspaceID, permission) {
+			return false
+		}
+		checked[project.WorkspaceID] = true
+	}
+	return true
+}
@@ -4,8 +4,8 @@ import (
 	"TimeTrack-api/src/services"
 	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
+	"errors"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -14,11 +14,13 @@ import (
 )
 
 type ProjectHandler struct {
-	service *services.ProjectService
+	service       *services.ProjectService
+	clientService *services.ClientService
+	permissions   *services.PermissionService
 }
 
-func NewProjectHandler(s *services.ProjectService) *ProjectHandler {
-	return &ProjectHandler{service: s}
+func NewProjectHandler(s *services.ProjectService, cs *services.ClientService, ps *services.PermissionService) *ProjectHandler {
+	return &ProjectHandler{service: s, clientService: cs, permissions: ps}
 }
 
 func (h *ProjectHandler) Create(c *gin.Context) {
@@ -32,11 +34,25 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 		Name:        input.Name,
 		Integration: models.IntegrationInfo(input.Integration),
 		OwnerID:     c.GetString("user_id"),
+		WorkspaceID: c.GetString("workspace_id"),
+		ClientID:    input.ClientID,
+		Billable:    input.Billable,
 	}
 
 	project.OwnerID = c.GetString("user_id")
 
+	if !authorize(c, h.permissions, project.WorkspaceID, services.PermCreateProject) {
+		return
+	}
+	if !h.checkClient(c, project.ClientID, project.WorkspaceID) {
+		return
+	}
+
 	if err := h.service.CreateProject(c, &project); err != nil {
+		if errors.Is(err, services.ErrInvalidIntegration) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid integration", "details": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create project"})
 		return
 	}
@@ -51,21 +67,41 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
 		return
 	}
+
+	project, err := h.service.GetProjectByID(c, id, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if !authorize(c, h.permissions, project.WorkspaceID, services.PermManageProjects) {
+		return
+	}
+
 	update := bson.M{}
 	if input.Name != nil {
 		update["name"] = *input.Name
 	}
 	if input.Integration != nil {
-		update["integration"] = models.IntegrationInfo(*input.Integration)
+		integration, err := h.service.LinkIntegration(c.GetString("user_id"), models.IntegrationInfo(*input.Integration))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid integration", "details": err.Error()})
+			return
+		}
+		update["integration"] = integration
+	}
+	if input.ClientID != nil {
+		if !h.checkClient(c, *input.ClientID, project.WorkspaceID) {
+			return
+		}
+		update["client_id"] = *input.ClientID
+	}
+	if input.Billable != nil {
+		update["billable"] = *input.Billable
 	}
-	update["owner_id"] = c.GetString("user_id")
 	update["updated_at"] = time.Now()
 	update["deleted_at"] = nil
-
-	_, err := h.service.GetProjectByID(c, id, c.GetString("user_id"))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
-		return
+	if project.WorkspaceID == "" {
+		update["owner_id"] = c.GetString("user_id")
 	}
 
 	if err := h.service.UpdateProject(c, id, update); err != nil {
@@ -75,8 +111,29 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// checkClient responds with 400 unless the client is empty or belongs to the
+// workspace of the project, or to the user for personal projects.
+func (h *ProjectHandler) checkClient(c *gin.Context, clientID string, workspaceID string) bool {
+	if clientID == "" {
+		return true
+	}
+	if _, err := h.clientService.GetClient(c, clientID, c.GetString("user_id"), workspaceID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client", "details": "client not found"})
+		return false
+	}
+	return true
+}
+
 func (h *ProjectHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
+	project, err := h.service.GetProjectByID(c, id, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if !authorize(c, h.permissions, project.WorkspaceID, services.PermManageProjects) {
+		return
+	}
 	if err := h.service.DeleteProject(c, id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
 		return
@@ -87,8 +144,7 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 func (h *ProjectHandler) List(c *gin.Context) {
 	ownerID := c.GetString("user_id")
 	name := c.Query("name")
-	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
-	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	cursor, skip, limit := pageParams(c)
 
 	// check if "ids" query parameter is provided, and if so parse it it is comma separated
 	idsParam := c.Query("ids")
@@ -100,10 +156,14 @@ func (h *ProjectHandler) List(c *gin.Context) {
 		}
 	}
 
-	projects, err := h.service.GetProjects(c, ownerID, name, ids, skip, limit)
+	page, err := h.service.GetProjects(c, ownerID, c.GetString("workspace_id"), name, ids, cursor, skip, limit)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
 		return
 	}
-	c.JSON(http.StatusOK, projects)
+	c.JSON(http.StatusOK, page)
 }
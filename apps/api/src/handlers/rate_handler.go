@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RateHandler struct {
+	service          *services.RateService
+	clientService    *services.ClientService
+	projectService   *services.ProjectService
+	workspaceService *services.WorkspaceService
+	permissions      *services.PermissionService
+}
+
+func NewRateHandler(s *services.RateService, cs *services.ClientService, ps *services.ProjectService, ws *services.WorkspaceService, perms *services.PermissionService) *RateHandler {
+	return &RateHandler{service: s, clientService: cs, projectService: ps, workspaceService: ws, permissions: perms}
+}
+
+// Create adds an hourly rate in the active workspace, or a personal rate. The
+// rate replaces the previous one for the same target from its effective date.
+func (h *RateHandler) Create(c *gin.Context) {
+	var input dtos.CreateRateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	workspaceID := c.GetString("workspace_id")
+	if !authorize(c, h.permissions, workspaceID, services.PermManageBilling) {
+		return
+	}
+
+	rate := models.Rate{
+		OwnerID:     userID,
+		WorkspaceID: workspaceID,
+		ClientID:    input.ClientID,
+		ProjectID:   input.ProjectID,
+		UserID:      input.UserID,
+		HourlyRate:  input.HourlyRate,
+		Currency:    strings.ToUpper(input.Currency),
+	}
+	if input.EffectiveFrom != nil {
+		rate.EffectiveFrom = *input.EffectiveFrom
+	}
+	if details := h.checkTarget(c, &rate); details != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rate", "details": details})
+		return
+	}
+
+	if err := h.service.CreateRate(c, &rate); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create rate"})
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+// List returns the rates of the active workspace, or the personal rates, the
+// latest effective first. The client, project and user query parameters
+// return the history of a single target.
+func (h *RateHandler) List(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	if !authorize(c, h.permissions, workspaceID, services.PermManageBilling) {
+		return
+	}
+
+	var target *models.Rate
+	if c.Query("client") != "" || c.Query("project") != "" || c.Query("user") != "" {
+		target = &models.Rate{ClientID: c.Query("client"), ProjectID: c.Query("project"), UserID: c.Query("user")}
+	}
+	rates, err := h.service.GetRates(c, c.GetString("user_id"), workspaceID, target)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *RateHandler) Delete(c *gin.Context) {
+	workspaceID := c.GetString("workspace_id")
+	if !authorize(c, h.permissions, workspaceID, services.PermManageBilling) {
+		return
+	}
+
+	if err := h.service.DeleteRate(c, c.Param("id"), c.GetString("user_id"), workspaceID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rate not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// checkTarget returns why the client, project and user of the rate cannot be
+// billed in the scope of the rate, or an empty string when they can.
+func (h *RateHandler) checkTarget(c *gin.Context, rate *models.Rate) string {
+	if rate.ClientID == "" && rate.ProjectID == "" && rate.UserID == "" {
+		return "a client, project or user is required"
+	}
+	if rate.ClientID != "" && rate.ProjectID != "" {
+		return "a rate is for a client or a project, not both"
+	}
+	if rate.ClientID != "" {
+		if _, err := h.clientService.GetClient(c, rate.ClientID, rate.OwnerID, rate.WorkspaceID); err != nil {
+			return "client not found"
+		}
+	}
+	if rate.ProjectID != "" {
+		project, err := h.projectService.GetProjectByID(c, rate.ProjectID, rate.OwnerID)
+		if err != nil || project.WorkspaceID != rate.WorkspaceID {
+			return "project not found"
+		}
+	}
+	if rate.UserID != "" {
+		if rate.WorkspaceID == "" {
+			if rate.UserID != rate.OwnerID {
+				return "personal rates can only be set for yourself"
+			}
+			return ""
+		}
+		workspace, err := h.workspaceService.GetWorkspace(c, rate.WorkspaceID, rate.OwnerID)
+		if err != nil || services.RoleOf(workspace, rate.UserID) == "" {
+			return "user is not a member of the workspace"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	timeEntryService *services.TimeEntryService
+	clientService    *services.ClientService
+	workspaceService *services.WorkspaceService
+	permissions      *services.PermissionService
+}
+
+func NewReportHandler(tes *services.TimeEntryService, cs *services.ClientService, ws *services.WorkspaceService, ps *services.PermissionService) *ReportHandler {
+	return &ReportHandler{timeEntryService: tes, clientService: cs, workspaceService: ws, permissions: ps}
+}
+
+// Monthly renders the timesheet of a month as a PDF or HTML file. The client
+// query parameter limits it to the projects of a client, and user selects a
+// member of the active workspace, or all of them, like the statistics.
+func (h *ReportHandler) Monthly(c *gin.Context) {
+	userID := c.GetString("user_id")
+	workspaceID := c.GetString("workspace_id")
+	month := c.Query("month")
+	format := c.DefaultQuery("format", "pdf")
+
+	contentType, ok := services.ReportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidReportFormat.Error()})
+		return
+	}
+
+	var client *models.Client
+	if clientID := c.Query("client"); clientID != "" {
+		var err error
+		if client, err = h.clientService.GetClient(c, clientID, userID, workspaceID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+			return
+		}
+	}
+
+	var members []models.WorkspaceMember
+	if user := c.Query("user"); user != "" {
+		if workspaceID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Reports of other users require an active workspace"})
+			return
+		}
+		if !authorize(c, h.permissions, workspaceID, services.PermViewTeam) {
+			return
+		}
+		workspace, err := h.workspaceService.GetWorkspace(c, workspaceID, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Report failed"})
+			return
+		}
+		if members = selectMembers(workspace, user); len(members) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User is not a member of the workspace"})
+			return
+		}
+	}
+
+	report, err := h.timeEntryService.GetMonthlyReport(c, userID, workspaceID, members, client, month)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMonth) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Report failed"})
+		return
+	}
+
+	// Rendered in full first, so a failure can still be reported as an error.
+	var buf bytes.Buffer
+	if err := services.RenderMonthlyReport(&buf, report, format); err != nil {
+		log.Printf("Error rendering report for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Report failed"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="timesheet-%s.%s"`, month, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	service *services.SettingsService
+}
+
+func NewSettingsHandler(s *services.SettingsService) *SettingsHandler {
+	return &SettingsHandler{service: s}
+}
+
+func (h *SettingsHandler) Get(c *gin.Context) {
+	settings, err := h.service.GetSettings(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching settings"})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *SettingsHandler) Update(c *gin.Context) {
+	var input dtos.UpdateUserSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(c, c.GetString("user_id"), &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating settings"})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
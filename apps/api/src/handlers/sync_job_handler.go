@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SyncJobHandler struct {
+	service *services.SyncJobService
+}
+
+func NewSyncJobHandler(s *services.SyncJobService) *SyncJobHandler {
+	return &SyncJobHandler{service: s}
+}
+
+func (h *SyncJobHandler) List(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && status != services.SyncStatusPending && status != services.SyncStatusFailed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, must be 'pending' or 'failed'"})
+		return
+	}
+
+	jobs, err := h.service.GetOpenJobs(c, c.GetString("user_id"), status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (h *SyncJobHandler) Retry(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.RetryJob(c, id, c.GetString("user_id")); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sync job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Retry failed"})
+		return
+	}
+	c.Status(http.StatusOK)
+}
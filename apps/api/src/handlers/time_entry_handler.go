@@ -4,21 +4,28 @@ import (
 	"TimeTrack-api/src/services"
 	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type TimeEntryHandler struct {
-	service        *services.TimeEntryService
-	projectService *services.ProjectService
+	service          *services.TimeEntryService
+	projectService   *services.ProjectService
+	workspaceService *services.WorkspaceService
+	permissions      *services.PermissionService
 }
 
-func NewTimeEntryHandler(s *services.TimeEntryService, ps *services.ProjectService) *TimeEntryHandler {
-	return &TimeEntryHandler{service: s, projectService: ps}
+func NewTimeEntryHandler(s *services.TimeEntryService, ps *services.ProjectService, ws *services.WorkspaceService, perms *services.PermissionService) *TimeEntryHandler {
+	return &TimeEntryHandler{service: s, projectService: ps, workspaceService: ws, permissions: perms}
 }
 
 func (h *TimeEntryHandler) Create(c *gin.Context) {
@@ -29,11 +36,14 @@ func (h *TimeEntryHandler) Create(c *gin.Context) {
 	}
 
 	// Validate project ID
-	_, err := h.projectService.GetProjectByID(c, input.ProjectID, c.GetString("user_id"))
+	project, err := h.projectService.GetProjectByID(c, input.ProjectID, c.GetString("user_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
 		return
 	}
+	if !authorize(c, h.permissions, project.WorkspaceID, services.PermLogTime) {
+		return
+	}
 
 	// Calculate duration
 	duration := input.Period.End.Sub(input.Period.Start)
@@ -54,18 +64,53 @@ func (h *TimeEntryHandler) Create(c *gin.Context) {
 			Ended:    input.Period.End,
 			Duration: i,
 		},
-		Note: input.Note,
+		Note:     input.Note,
+		Billable: project.Billable,
+		Tags:     services.NormalizeTags(input.Tags),
+	}
+	if input.Billable != nil {
+		entry.Billable = *input.Billable
 	}
 
 	entry.OwnerID = c.GetString("user_id")
 
 	if err := h.service.CreateTimeEntry(c, &entry); err != nil {
+		if respondOverlap(c, err) || respondLocked(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Creation failed"})
 		return
 	}
 	c.JSON(http.StatusOK, entry)
 }
 
+func (h *TimeEntryHandler) BulkCreate(c *gin.Context) {
+	var input dtos.BulkCreateTimeEntriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	workspaceID := c.GetString("workspace_id")
+	if !authorize(c, h.permissions, workspaceID, services.PermLogTime) {
+		return
+	}
+	if input.CreateProjects && !authorize(c, h.permissions, workspaceID, services.PermCreateProject) {
+		return
+	}
+
+	result, err := h.service.BulkCreateTimeEntries(c, c.GetString("user_id"), workspaceID, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bulk creation failed"})
+		return
+	}
+	if result.Invalid > 0 && !result.DryRun {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 func (h *TimeEntryHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 
@@ -75,13 +120,20 @@ func (h *TimeEntryHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.requireOwnEntry(c, id); !ok {
+		return
+	}
+
 	// Validate project ID
 	if input.ProjectID != nil {
-		_, err := h.projectService.GetProjectByID(c, *input.ProjectID, c.GetString("user_id"))
+		project, err := h.projectService.GetProjectByID(c, *input.ProjectID, c.GetString("user_id"))
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
 			return
 		}
+		if !authorize(c, h.permissions, project.WorkspaceID, services.PermLogTime) {
+			return
+		}
 	}
 
 	// Update duration
@@ -111,28 +163,286 @@ func (h *TimeEntryHandler) Update(c *gin.Context) {
 	if input.Note != nil {
 		update["note"] = *input.Note
 	}
+	if input.Billable != nil {
+		update["billable"] = *input.Billable
+	}
+	if input.Tags != nil {
+		update["tags"] = services.NormalizeTags(*input.Tags)
+	}
 
-	if err := h.service.UpdateTimeEntry(c, id, update); err != nil {
+	conflicts, err := h.service.UpdateTimeEntry(c, id, update)
+	if err != nil {
+		if respondOverlap(c, err) || respondLocked(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
 		return
 	}
+	if len(conflicts) > 0 {
+		c.JSON(http.StatusOK, gin.H{"conflicts": conflicts})
+		return
+	}
 	c.Status(http.StatusOK)
 }
 
+// requireOwnEntry responds with 404 unless the entry belongs to the user of the
+// request, since entries can only be changed by their owner, and with 403
+// unless the user may log time in the workspace of its project.
+func (h *TimeEntryHandler) requireOwnEntry(c *gin.Context, id string) (*models.TimeEntry, bool) {
+	entry, err := h.service.GetTimeEntryByID(c, id, c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Time entry not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get time entry"})
+		}
+		return nil, false
+	}
+	if !authorizeProjects(c, h.permissions, h.projectService, []string{entry.ProjectID}, services.PermLogTime) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// respondOverlap writes a 409 listing the conflicting entries when err is an
+// overlap rejection, and reports whether it did.
+func respondOverlap(c *gin.Context, err error) bool {
+	var overlapErr *services.OverlapError
+	if !errors.As(err, &overlapErr) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "Time entry overlaps existing entries", "conflicts": overlapErr.Conflicts})
+	return true
+}
+
+// respondLocked writes a 423 when err is a change to an entry in a submitted or
+// approved timesheet, and reports whether it did. It is not a 409, which
+// clients read as an overlap with other entries.
+func respondLocked(c *gin.Context, err error) bool {
+	if !errors.Is(err, services.ErrEntryLocked) {
+		return false
+	}
+	c.JSON(http.StatusLocked, gin.H{"error": "Time entry is locked", "details": err.Error()})
+	return true
+}
+
 func (h *TimeEntryHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
+	if _, ok := h.requireOwnEntry(c, id); !ok {
+		return
+	}
 	if err := h.service.DeleteTimeEntry(c, id); err != nil {
+		if respondLocked(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
 		return
 	}
 	c.Status(http.StatusOK)
 }
 
+func (h *TimeEntryHandler) Report(c *gin.Context) {
+	var input dtos.ReportTimeEntriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	// Reporting logs the time in the integration of each project.
+	projectIDs := make([]string, 0, len(input.IDs))
+	for _, id := range input.IDs {
+		if entry, err := h.service.GetTimeEntryByID(c, id, c.GetString("user_id")); err == nil {
+			projectIDs = append(projectIDs, entry.ProjectID)
+		}
+	}
+	if !authorizeProjects(c, h.permissions, h.projectService, projectIDs, services.PermLogTime) {
+		return
+	}
+
+	results := h.service.ReportTimeEntries(c, c.GetString("user_id"), input.IDs)
+	c.JSON(http.StatusOK, results)
+}
+
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+	var input dtos.StartTimerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	// Validate project ID
+	project, err := h.projectService.GetProjectByID(c, input.ProjectID, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+	if !authorize(c, h.permissions, project.WorkspaceID, services.PermLogTime) {
+		return
+	}
+
+	entry := models.TimeEntry{
+		ProjectID: input.ProjectID,
+		OwnerID:   c.GetString("user_id"),
+		Note:      input.Note,
+		Billable:  project.Billable,
+		Tags:      services.NormalizeTags(input.Tags),
+	}
+	if input.Billable != nil {
+		entry.Billable = *input.Billable
+	}
+
+	if err := h.service.StartTimer(c, &entry); err != nil {
+		if respondLocked(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrTimerAlreadyRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A timer is already running"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start timer"})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+	var input dtos.StopTimerInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+			return
+		}
+	}
+
+	running, err := h.service.GetRunningTimeEntry(c, c.GetString("user_id"))
+	if err == nil && !authorizeProjects(c, h.permissions, h.projectService, []string{running.ProjectID}, services.PermLogTime) {
+		return
+	}
+
+	entry, err := h.service.StopTimer(c, c.GetString("user_id"), input.Note)
+	if err != nil {
+		if respondOverlap(c, err) || respondLocked(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrNoTimerRunning) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No timer is running"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not stop timer"})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *TimeEntryHandler) GetTimer(c *gin.Context) {
+	entry, err := h.service.GetRunningTimeEntry(c, c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No timer is running"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get timer"})
+		return
+	}
+	if !authorizeProjects(c, h.permissions, h.projectService, []string{entry.ProjectID}, services.PermLogTime) {
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+func (h *TimeEntryHandler) Get(c *gin.Context) {
+	entry, ok := h.requireOwnEntry(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// List returns a page of the user's entries, newest first, with the total
+// count and the cursor of the next page. Besides the date range they can be
+// filtered by projects, words of the note, reported status, the integration of
+// the project, duration in seconds and tags, which entries must all have.
 func (h *TimeEntryHandler) List(c *gin.Context) {
 	ownerID := c.GetString("user_id")
 	fromStr, toStr := c.Query("from"), c.Query("to")
-	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
-	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	cursor, skip, limit := pageParams(c)
+
+	filter := services.TimeEntryFilter{
+		ProjectIDs:  c.QueryArray("project"),
+		Text:        strings.TrimSpace(c.Query("q")),
+		Integration: c.Query("integration"),
+		Tags:        services.NormalizeTags(c.QueryArray("tag")),
+	}
+	if fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err == nil {
+			filter.From = &t
+		}
+	}
+	if toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err == nil {
+			filter.To = &t
+		}
+	}
+	if reportedStr := c.Query("reported"); reportedStr != "" {
+		reported, err := strconv.ParseBool(reportedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reported filter", "details": "must be true or false"})
+			return
+		}
+		filter.Reported = &reported
+	}
+	if !bindSeconds(c, "min_duration", &filter.MinDuration) || !bindSeconds(c, "max_duration", &filter.MaxDuration) {
+		return
+	}
+
+	page, err := h.service.GetTimeEntries(c, ownerID, filter, cursor, skip, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		return
+	}
+	projectIDs := append([]string{}, filter.ProjectIDs...)
+	for _, entry := range page.Entries {
+		projectIDs = append(projectIDs, entry.ProjectID)
+	}
+	if !authorizeProjects(c, h.permissions, h.projectService, projectIDs, services.PermLogTime) {
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// bindSeconds parses the query parameter as a number of seconds into target,
+// leaving it unchanged when the parameter is missing. It responds with 400 and
+// returns false when the value is invalid.
+func bindSeconds(c *gin.Context, param string, target *int) bool {
+	value := c.Query(param)
+	if value == "" {
+		return true
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " filter", "details": "must be a number of seconds"})
+		return false
+	}
+	*target = seconds
+	return true
+}
+
+func (h *TimeEntryHandler) Export(c *gin.Context) {
+	ownerID := c.GetString("user_id")
+	fromStr, toStr := c.Query("from"), c.Query("to")
+	format := c.DefaultQuery("format", "csv")
+
+	contentType, ok := services.ExportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidExportFormat.Error()})
+		return
+	}
 
 	var from, to *time.Time
 	if fromStr != "" {
@@ -148,12 +458,23 @@ func (h *TimeEntryHandler) List(c *gin.Context) {
 		}
 	}
 
-	entries, err := h.service.GetTimeEntries(c, ownerID, from, to, skip, limit)
+	// The file is streamed, so the projects are checked before it starts.
+	projectIDs, err := h.service.GetEntryProjectIDs(c, ownerID, from, to)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Export failed"})
+		return
+	}
+	if !authorizeProjects(c, h.permissions, h.projectService, projectIDs, services.PermLogTime) {
 		return
 	}
-	c.JSON(http.StatusOK, entries)
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="time-entries.%s"`, format))
+	c.Status(http.StatusOK)
+	if err := h.service.ExportTimeEntries(c, c.Writer, ownerID, from, to, format); err != nil {
+		// Headers are already sent, so the client only sees a truncated file.
+		log.Printf("Error exporting time entries for user %s: %v", ownerID, err)
+	}
 }
 
 func (h *TimeEntryHandler) Statistics(c *gin.Context) {
@@ -175,6 +496,20 @@ func (h *TimeEntryHandler) Statistics(c *gin.Context) {
 		}
 	}
 
+	if user := c.Query("user"); user != "" {
+		h.teamStatistics(c, user, from, to, format)
+		return
+	}
+
+	projectIDs, err := h.service.GetEntryProjectIDs(c, ownerID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Statistics failed"})
+		return
+	}
+	if !authorizeProjects(c, h.permissions, h.projectService, projectIDs, services.PermLogTime) {
+		return
+	}
+
 	stats, err := h.service.GetTimeEntryStatistics(c, ownerID, from, to, format)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Statistics failed"})
@@ -182,3 +517,49 @@ func (h *TimeEntryHandler) Statistics(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, stats)
 }
+
+// teamStatistics responds with the statistics of a member of the active
+// workspace, or of all members when user is "all". It requires PermViewTeam.
+func (h *TimeEntryHandler) teamStatistics(c *gin.Context, user string, from, to *time.Time, format string) {
+	workspaceID := c.GetString("workspace_id")
+	if workspaceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Statistics of other users require an active workspace"})
+		return
+	}
+	if !authorize(c, h.permissions, workspaceID, services.PermViewTeam) {
+		return
+	}
+
+	workspace, err := h.workspaceService.GetWorkspace(c, workspaceID, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Statistics failed"})
+		return
+	}
+	var memberIDs []string
+	for _, member := range selectMembers(workspace, user) {
+		memberIDs = append(memberIDs, member.UserID)
+	}
+	if len(memberIDs) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User is not a member of the workspace"})
+		return
+	}
+
+	stats, err := h.service.GetTeamStatistics(c, c.GetString("user_id"), workspaceID, memberIDs, from, to, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Statistics failed"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// selectMembers returns the member of the workspace with the ID or email, or
+// all members when user is "all".
+func selectMembers(workspace *models.Workspace, user string) []models.WorkspaceMember {
+	var members []models.WorkspaceMember
+	for _, member := range workspace.Members {
+		if user == "all" || member.UserID == user || strings.EqualFold(member.Email, user) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
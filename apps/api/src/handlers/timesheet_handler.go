@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TimesheetHandler struct {
+	service          *services.TimesheetService
+	timeEntryService *services.TimeEntryService
+	permissions      *services.PermissionService
+}
+
+func NewTimesheetHandler(s *services.TimesheetService, tes *services.TimeEntryService, ps *services.PermissionService) *TimesheetHandler {
+	return &TimesheetHandler{service: s, timeEntryService: tes, permissions: ps}
+}
+
+// Submit submits the week of the user in the active workspace for approval.
+func (h *TimesheetHandler) Submit(c *gin.Context) {
+	var input dtos.SubmitTimesheetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	workspaceID, ok := requireWorkspace(c)
+	if !ok || !authorize(c, h.permissions, workspaceID, services.PermLogTime) {
+		return
+	}
+
+	timesheet, err := h.timeEntryService.SubmitTimesheet(c, c.GetString("user_id"), workspaceID, strings.TrimSpace(input.Week))
+	if err != nil {
+		respondTimesheetError(c, err, "Submit failed")
+		return
+	}
+	c.JSON(http.StatusOK, timesheet)
+}
+
+// List returns the timesheets of the user, in the active workspace if any.
+func (h *TimesheetHandler) List(c *gin.Context) {
+	status, ok := timesheetStatus(c, "")
+	if !ok {
+		return
+	}
+	timesheets, err := h.service.GetTimesheets(c, c.GetString("user_id"), c.GetString("workspace_id"), status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		return
+	}
+	c.JSON(http.StatusOK, timesheets)
+}
+
+// ListForReview returns the timesheets of all members of the active workspace,
+// by default the ones waiting for review.
+func (h *TimesheetHandler) ListForReview(c *gin.Context) {
+	status, ok := timesheetStatus(c, models.TimesheetSubmitted)
+	if !ok {
+		return
+	}
+	workspaceID, ok := requireWorkspace(c)
+	if !ok || !authorize(c, h.permissions, workspaceID, services.PermReviewTimesheets) {
+		return
+	}
+
+	timesheets, err := h.service.GetTimesheets(c, "", workspaceID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		return
+	}
+	c.JSON(http.StatusOK, timesheets)
+}
+
+// Get returns the timesheet with its entries to its owner and its reviewers.
+func (h *TimesheetHandler) Get(c *gin.Context) {
+	timesheet, ok := h.getTimesheet(c)
+	if !ok {
+		return
+	}
+	if timesheet.OwnerID != c.GetString("user_id") &&
+		!authorize(c, h.permissions, timesheet.WorkspaceID, services.PermReviewTimesheets) {
+		return
+	}
+
+	entries, err := h.timeEntryService.GetTimesheetEntries(c, timesheet)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get timesheet entries"})
+		return
+	}
+	timesheet.Entries = entries
+	c.JSON(http.StatusOK, timesheet)
+}
+
+func (h *TimesheetHandler) Approve(c *gin.Context) {
+	h.review(c, models.TimesheetApproved)
+}
+
+func (h *TimesheetHandler) Reject(c *gin.Context) {
+	h.review(c, models.TimesheetRejected)
+}
+
+func (h *TimesheetHandler) review(c *gin.Context, status string) {
+	var input dtos.ReviewTimesheetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+	if status == models.TimesheetRejected && strings.TrimSpace(input.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A comment is required to reject a timesheet"})
+		return
+	}
+
+	timesheet, ok := h.getTimesheet(c)
+	if !ok || !authorize(c, h.permissions, timesheet.WorkspaceID, services.PermReviewTimesheets) {
+		return
+	}
+
+	reviewed, err := h.service.Review(c, timesheet, c.GetString("user_id"), status, strings.TrimSpace(input.Comment))
+	if err != nil {
+		respondTimesheetError(c, err, "Review failed")
+		return
+	}
+	c.JSON(http.StatusOK, reviewed)
+}
+
+func (h *TimesheetHandler) getTimesheet(c *gin.Context) (*models.Timesheet, bool) {
+	timesheet, err := h.service.GetTimesheet(c, c.Param("id"))
+	if err != nil {
+		respondTimesheetError(c, err, "Could not get timesheet")
+		return nil, false
+	}
+	return timesheet, true
+}
+
+// requireWorkspace returns the active workspace, or responds with 400 when the
+// request has none.
+func requireWorkspace(c *gin.Context) (string, bool) {
+	workspaceID := c.GetString("workspace_id")
+	if workspaceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An active workspace is required", "details": "set the X-Workspace-ID header"})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func timesheetStatus(c *gin.Context, fallback string) (string, bool) {
+	status := c.DefaultQuery("status", fallback)
+	switch status {
+	case "", models.TimesheetSubmitted, models.TimesheetApproved, models.TimesheetRejected:
+		return status, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, must be 'submitted', 'approved' or 'rejected'"})
+	return "", false
+}
+
+func respondTimesheetError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Timesheet not found"})
+	case errors.Is(err, services.ErrInvalidWeek):
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+	case errors.Is(err, services.ErrOwnTimesheet):
+		c.JSON(http.StatusForbidden, gin.H{"error": message, "details": err.Error()})
+	case errors.Is(err, services.ErrTimesheetLocked), errors.Is(err, services.ErrTimesheetNotSubmitted):
+		c.JSON(http.StatusConflict, gin.H{"error": message, "details": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
 )
 
@@ -47,24 +50,87 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 }
 
 func (h *UserHandler) LoginUser(c *gin.Context) {
-	var loginData models.User
-	if err := c.ShouldBindJSON(&loginData); err != nil {
+	var input dtos.LoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	user, err := h.userService.LoginUser(c, &loginData)
+	user, err := h.userService.LoginUser(c, &models.User{Email: input.Email, Password: input.Password})
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	tokenString, err := h.tokenService.GenerateAuthToken(user.ID, user.Email)
+	deviceName := input.DeviceName
+	if deviceName == "" {
+		deviceName = c.Request.UserAgent()
+	}
+	tokens, err := h.tokenService.CreateSession(c, user.ID, user.Email, deviceName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing the token"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var input dtos.RefreshTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	tokens, err := h.tokenService.RefreshSession(c, input.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error refreshing the token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revokes the session the request was made with. Tokens issued before
+// sessions existed have no session and only expire.
+func (h *UserHandler) Logout(c *gin.Context) {
+	sessionID := c.GetString("session_id")
+	if sessionID != "" {
+		err := h.tokenService.RevokeSession(c, c.GetString("user_id"), sessionID)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking the session"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.tokenService.ListSessions(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching sessions"})
+		return
+	}
+	current := c.GetString("session_id")
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == current
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	err := h.tokenService.RevokeSession(c, c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking the session"})
+		return
+	}
+	c.Status(http.StatusOK)
 }
 
 func (h *UserHandler) GetUser(c *gin.Context) {
@@ -76,3 +142,24 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, user)
 }
+
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	var input dtos.UpdateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if err := h.userService.UpdateUser(c, userID, &input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating user"})
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
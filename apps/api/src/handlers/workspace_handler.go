@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WorkspaceHandler struct {
+	service      *services.WorkspaceService
+	userService  *services.UserService
+	permissions  *services.PermissionService
+	auditService *services.AuditService
+}
+
+func NewWorkspaceHandler(s *services.WorkspaceService, us *services.UserService, ps *services.PermissionService, as *services.AuditService) *WorkspaceHandler {
+	return &WorkspaceHandler{service: s, userService: us, permissions: ps, auditService: as}
+}
+
+func (h *WorkspaceHandler) Create(c *gin.Context) {
+	var input dtos.CreateWorkspaceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+		return
+	}
+
+	owner := models.WorkspaceMember{UserID: user.ID, Email: user.Email}
+	workspace, err := h.service.CreateWorkspace(c, owner, input.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create workspace"})
+		return
+	}
+	c.JSON(http.StatusOK, workspace)
+}
+
+func (h *WorkspaceHandler) List(c *gin.Context) {
+	workspaces, err := h.service.GetWorkspaces(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "List failed"})
+		return
+	}
+	c.JSON(http.StatusOK, workspaces)
+}
+
+func (h *WorkspaceHandler) Get(c *gin.Context) {
+	workspace, err := h.service.GetWorkspace(c, c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching workspace"})
+		return
+	}
+	c.JSON(http.StatusOK, workspace)
+}
+
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	var input dtos.AddWorkspaceMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	workspaceID := c.Param("id")
+	if !authorize(c, h.permissions, workspaceID, services.PermManageMembers) {
+		return
+	}
+	if input.Role == models.RoleAdmin && !authorize(c, h.permissions, workspaceID, services.PermManageAdmins) {
+		return
+	}
+
+	user, err := h.userService.GetUserByEmail(c, input.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found", "details": "no user is registered with " + input.Email})
+		return
+	}
+
+	member := models.WorkspaceMember{UserID: user.ID, Email: user.Email, Role: input.Role}
+	workspace, err := h.service.AddMember(c, workspaceID, member)
+	if err != nil {
+		respondWorkspaceError(c, err, "Could not add member")
+		return
+	}
+	c.JSON(http.StatusOK, workspace)
+}
+
+// RemoveMember removes a member from the workspace. Members can always remove
+// themselves to leave.
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	workspace, ok := h.getWorkspace(c)
+	if !ok {
+		return
+	}
+	memberID := c.Param("userId")
+	if memberID != c.GetString("user_id") && !h.authorizeMemberChange(c, workspace, memberID, "") {
+		return
+	}
+
+	if err := h.service.RemoveMember(c, workspace, memberID); err != nil {
+		respondWorkspaceError(c, err, "Could not remove member")
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	var input dtos.UpdateWorkspaceMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	workspace, ok := h.getWorkspace(c)
+	if !ok {
+		return
+	}
+	memberID := c.Param("userId")
+	if !h.authorizeMemberChange(c, workspace, memberID, input.Role) {
+		return
+	}
+
+	updated, err := h.service.SetMemberRole(c, workspace, memberID, input.Role)
+	if err != nil {
+		respondWorkspaceError(c, err, "Could not update member")
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// Audit returns the latest denied requests in the workspace.
+func (h *WorkspaceHandler) Audit(c *gin.Context) {
+	workspaceID := c.Param("id")
+	if !authorize(c, h.permissions, workspaceID, services.PermViewAudit) {
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "100"), 10, 64)
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit, must be between 1 and 1000"})
+		return
+	}
+
+	events, err := h.auditService.GetWorkspaceEvents(c, workspaceID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching audit log"})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *WorkspaceHandler) getWorkspace(c *gin.Context) (*models.Workspace, bool) {
+	workspace, err := h.service.GetWorkspace(c, c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		respondWorkspaceError(c, err, "Error fetching workspace")
+		return nil, false
+	}
+	return workspace, true
+}
+
+// authorizeMemberChange checks that the user may change the member, and give
+// them newRole when set. Only owners may change admins or make members admins.
+func (h *WorkspaceHandler) authorizeMemberChange(c *gin.Context, workspace *models.Workspace, memberID string, newRole string) bool {
+	if !authorize(c, h.permissions, workspace.ID, services.PermManageMembers) {
+		return false
+	}
+	if newRole == models.RoleAdmin || services.RoleOf(workspace, memberID) == models.RoleAdmin {
+		return authorize(c, h.permissions, workspace.ID, services.PermManageAdmins)
+	}
+	return true
+}
+
+func respondWorkspaceError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Workspace or member not found"})
+	case errors.Is(err, services.ErrAlreadyMember), errors.Is(err, services.ErrOwnerCannotLeave), errors.Is(err, services.ErrOwnerRoleFixed):
+		c.JSON(http.StatusConflict, gin.H{"error": message, "details": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
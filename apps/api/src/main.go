@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -33,14 +35,47 @@ func main() {
 	// Initialize services
 	userService := services.NewUserService(database.Database)
 	tokenService := services.NewTokenService(database.Database, cfg.JWTSecret)
-	atlassianService := services.NewAtlassianService(cfg.AtlassianConfig, *userService)
-	projectService := services.NewProjectService(database.Database, atlassianService)
-	timeEntryService := services.NewTimeEntryService(database.Database, projectService, atlassianService)
+	apiTokenService := services.NewAPITokenService(database.Database)
+	oauthStateService := services.NewOAuthStateService(database.Database, cfg.JWTSecret)
+	atlassianService := services.NewAtlassianService(cfg.AtlassianConfig, *userService, oauthStateService)
+	gitLabService := services.NewGitLabService(cfg.GitLabConfig, userService)
+	integrationRegistry := services.NewIntegrationRegistry(atlassianService, gitLabService)
+	settingsService := services.NewSettingsService(database.Database)
+	workspaceService := services.NewWorkspaceService(database.Database)
+	auditService := services.NewAuditService(database.Database)
+	permissionService := services.NewPermissionService(workspaceService, auditService)
+	projectService := services.NewProjectService(database.Database, integrationRegistry, settingsService, workspaceService)
+	clientService := services.NewClientService(database.Database, projectService)
+	rateService := services.NewRateService(database.Database)
+	syncJobService := services.NewSyncJobService(database.Database)
+	timesheetService := services.NewTimesheetService(database.Database)
+	timeEntryService := services.NewTimeEntryService(database.Database, projectService, integrationRegistry, syncJobService, userService, timesheetService, rateService)
+	if err := timeEntryService.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Could not create time entry indexes: %v", err)
+	}
+	if err := timesheetService.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Could not create timesheet indexes: %v", err)
+	}
+
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go services.NewSyncWorker(syncJobService, timeEntryService, 30*time.Second).Run(workerCtx)
+	go services.NewReconcileWorker(userService, timeEntryService, 6*time.Hour, 14*24*time.Hour).Run(workerCtx)
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userService, tokenService)
-	projectHandler := handlers.NewProjectHandler(projectService)
-	timeEntryHandler := handlers.NewTimeEntryHandler(timeEntryService, projectService)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+	projectHandler := handlers.NewProjectHandler(projectService, clientService, permissionService)
+	clientHandler := handlers.NewClientHandler(clientService, permissionService)
+	rateHandler := handlers.NewRateHandler(rateService, clientService, projectService, workspaceService, permissionService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService, userService, permissionService, auditService)
+	timeEntryHandler := handlers.NewTimeEntryHandler(timeEntryService, projectService, workspaceService, permissionService)
+	timesheetHandler := handlers.NewTimesheetHandler(timesheetService, timeEntryService, permissionService)
+	reportHandler := handlers.NewReportHandler(timeEntryService, clientService, workspaceService, permissionService)
+	syncJobHandler := handlers.NewSyncJobHandler(syncJobService)
+	settingsHandler := handlers.NewSettingsHandler(settingsService)
+	integrationHandler := handlers.NewIntegrationHandler(integrationRegistry, atlassianService, gitLabService, timeEntryService, projectService, permissionService)
 	healthHandler := handlers.NewHealthHandler(database.Database, cfg.APIVersion)
 
 	// Setup Gin router
@@ -54,6 +89,7 @@ func main() {
 	{
 		apiV1.POST("/register", userHandler.RegisterUser)
 		apiV1.POST("/login", userHandler.LoginUser)
+		apiV1.POST("/token/refresh", userHandler.RefreshToken)
 
 		// Health check endpoint
 		apiV1.GET("/health", healthHandler.CheckHealth)
@@ -64,30 +100,89 @@ func main() {
 		// Oauth Callback routes
 		authGroup.GET("/user/oauth/atlassian/callback", atlassianService.HandleOAuthCallback)
 
-		authGroup.Use(middleware.AuthMiddleware())
+		authGroup.Use(middleware.AuthMiddleware(apiTokenService), middleware.WorkspaceMiddleware(workspaceService))
 		{
+			authGroup.POST("/logout", userHandler.Logout)
+
 			userGroup := authGroup.Group("/user")
 			{
 				userGroup.GET("/", userHandler.GetUser)
+				userGroup.PATCH("/", userHandler.UpdateUser)
+				userGroup.GET("/sessions", userHandler.ListSessions)
+				userGroup.DELETE("/sessions/:id", userHandler.RevokeSession)
+				userGroup.GET("/tokens", apiTokenHandler.List)
+				userGroup.POST("/tokens", apiTokenHandler.Create)
+				userGroup.DELETE("/tokens/:id", apiTokenHandler.Revoke)
+				userGroup.GET("/settings", settingsHandler.Get)
+				userGroup.PATCH("/settings", settingsHandler.Update)
 
 				oauthGroup := userGroup.Group("/oauth")
 				{
 					oauthGroup.GET("/atlassian", atlassianService.GetOAuthURL)
 				}
+
+				userGroup.PUT("/integrations/gitlab", integrationHandler.ConnectGitLab)
+				userGroup.DELETE("/integrations/gitlab", integrationHandler.DisconnectGitLab)
 			}
 
+			authGroup.GET("/integrations", integrationHandler.List)
+			authGroup.GET("/integrations/jira/search", integrationHandler.SearchJira)
+			authGroup.GET("/integrations/jira/reconcile", integrationHandler.ReconcileJira)
+			authGroup.POST("/integrations/jira/reconcile", integrationHandler.ResolveJiraDrift)
+			authGroup.POST("/integrations/jira/import", integrationHandler.ImportJiraWorklogs)
+
+			// Workspace routes
+			authGroup.POST("/workspaces", workspaceHandler.Create)
+			authGroup.GET("/workspaces", workspaceHandler.List)
+			authGroup.GET("/workspaces/:id", workspaceHandler.Get)
+			authGroup.POST("/workspaces/:id/members", workspaceHandler.AddMember)
+			authGroup.PATCH("/workspaces/:id/members/:userId", workspaceHandler.UpdateMember)
+			authGroup.DELETE("/workspaces/:id/members/:userId", workspaceHandler.RemoveMember)
+			authGroup.GET("/workspaces/:id/audit", workspaceHandler.Audit)
+
 			// Project routes
 			authGroup.POST("/projects", projectHandler.Create)
 			authGroup.PUT("/projects/:id", projectHandler.Update)
 			authGroup.DELETE("/projects/:id", projectHandler.Delete)
 			authGroup.GET("/projects", projectHandler.List)
 
+			// Billing routes
+			authGroup.POST("/clients", clientHandler.Create)
+			authGroup.GET("/clients", clientHandler.List)
+			authGroup.PUT("/clients/:id", clientHandler.Update)
+			authGroup.DELETE("/clients/:id", clientHandler.Delete)
+			authGroup.POST("/rates", rateHandler.Create)
+			authGroup.GET("/rates", rateHandler.List)
+			authGroup.DELETE("/rates/:id", rateHandler.Delete)
+
 			// Time Entry routes
 			authGroup.POST("/time-entries", timeEntryHandler.Create)
+			authGroup.POST("/time-entries/bulk", timeEntryHandler.BulkCreate)
 			authGroup.PUT("/time-entries/:id", timeEntryHandler.Update)
 			authGroup.DELETE("/time-entries/:id", timeEntryHandler.Delete)
 			authGroup.GET("/time-entries", timeEntryHandler.List)
+			authGroup.GET("/time-entries/:id", timeEntryHandler.Get)
 			authGroup.GET("/time-entries/statistics", timeEntryHandler.Statistics)
+			authGroup.GET("/time-entries/export", timeEntryHandler.Export)
+			authGroup.POST("/time-entries/report", timeEntryHandler.Report)
+			authGroup.GET("/time-entries/timer", timeEntryHandler.GetTimer)
+			authGroup.POST("/time-entries/timer/start", timeEntryHandler.StartTimer)
+			authGroup.POST("/time-entries/timer/stop", timeEntryHandler.StopTimer)
+
+			// Timesheet routes
+			authGroup.POST("/timesheets", timesheetHandler.Submit)
+			authGroup.GET("/timesheets", timesheetHandler.List)
+			authGroup.GET("/timesheets/review", timesheetHandler.ListForReview)
+			authGroup.GET("/timesheets/:id", timesheetHandler.Get)
+			authGroup.POST("/timesheets/:id/approve", timesheetHandler.Approve)
+			authGroup.POST("/timesheets/:id/reject", timesheetHandler.Reject)
+
+			// Report routes
+			authGroup.GET("/reports/monthly", reportHandler.Monthly)
+
+			// Integration sync routes
+			authGroup.GET("/sync-jobs", syncJobHandler.List)
+			authGroup.POST("/sync-jobs/:id/retry", syncJobHandler.Retry)
 		}
 	}
 
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"TimeTrack-api/src/services"
+	"TimeTrack-shared/models"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,7 +13,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware accepts both access tokens from a login and personal access
+// tokens. Requests made with a personal access token are limited to its scope.
+func AuthMiddleware(apiTokens *services.APITokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
 		if tokenString == "" {
@@ -21,6 +26,11 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
 
+		if strings.HasPrefix(tokenString, services.APITokenPrefix) {
+			authenticateAPIToken(c, apiTokens, tokenString)
+			return
+		}
+
 		jwtSecret := os.Getenv("JWT_SECRET")
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -44,6 +54,47 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		userID := claims["userId"].(string)
 		c.Set("user_id", userID)
+		if sessionID, ok := claims["sid"].(string); ok {
+			c.Set("session_id", sessionID)
+		}
 		c.Next()
 	}
 }
+
+func authenticateAPIToken(c *gin.Context, apiTokens *services.APITokenService, tokenString string) {
+	apiToken, err := apiTokens.Authenticate(c, tokenString)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or revoked token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking token"})
+		}
+		c.Abort()
+		return
+	}
+
+	if !scopeAllows(apiToken.Scope, c.Request.Method, c.FullPath()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Token scope does not allow this request", "details": "scope: " + apiToken.Scope})
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", apiToken.UserID)
+	c.Set("token_scope", apiToken.Scope)
+	c.Next()
+}
+
+// scopeAllows reports whether a token with the scope may make a request with
+// the method to the route.
+func scopeAllows(scope, method, route string) bool {
+	readOnly := method == http.MethodGet || method == http.MethodHead
+	switch scope {
+	case models.TokenScopeAdmin:
+		return true
+	case models.TokenScopeWrite:
+		return readOnly || strings.Contains(route, "/time-entries")
+	case models.TokenScopeRead:
+		return readOnly
+	}
+	return false
+}
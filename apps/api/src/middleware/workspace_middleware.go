@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"TimeTrack-api/src/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WorkspaceHeader selects the active workspace of a request. Without it the
+// request works with the personal projects of the user.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// WorkspaceMiddleware checks that the user is a member of the workspace in the
+// WorkspaceHeader and sets it as "workspace_id". It must run after
+// AuthMiddleware.
+func WorkspaceMiddleware(workspaces *services.WorkspaceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workspaceID := c.GetHeader(WorkspaceHeader)
+		if workspaceID == "" {
+			c.Next()
+			return
+		}
+
+		if _, err := workspaces.GetWorkspace(c, workspaceID, c.GetString("user_id")); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of the workspace"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking workspace"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("workspace_id", workspaceID)
+		c.Next()
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// APITokenPrefix starts every personal access token, which tells them apart
+// from JWTs in the Authorization header.
+const APITokenPrefix = "tt_"
+
+// lastUsedResolution limits how often the last used time of a token is
+// written, so scripts polling the API do not cause a write per request.
+const lastUsedResolution = time.Minute
+
+var ErrInvalidAPIToken = errors.New("invalid or revoked API token")
+
+type APITokenService struct {
+	tokenCollection *mongo.Collection
+}
+
+func NewAPITokenService(db *mongo.Database) *APITokenService {
+	return &APITokenService{tokenCollection: db.Collection("api_tokens")}
+}
+
+// CreateToken creates a personal access token for the user. The token is only
+// returned here, the database stores its hash.
+func (s *APITokenService) CreateToken(ctx context.Context, userID, name, scope string) (*models.CreatedAPIToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := APITokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	apiToken := models.APIToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Scope:     scope,
+		Prefix:    token[:len(APITokenPrefix)+6],
+		TokenHash: hashToken(token),
+		CreatedAt: time.Now(),
+	}
+	if _, err := s.tokenCollection.InsertOne(ctx, apiToken); err != nil {
+		return nil, err
+	}
+	return &models.CreatedAPIToken{APIToken: apiToken, Token: token}, nil
+}
+
+// ListTokens returns the tokens of the user that are not revoked, newest first.
+func (s *APITokenService) ListTokens(ctx context.Context, userID string) ([]models.APIToken, error) {
+	filter := bson.M{"user_id": userID, "revoked_at": bson.M{"$eq": nil}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := s.tokenCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	tokens := []models.APIToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes a token of the user, or returns mongo.ErrNoDocuments.
+func (s *APITokenService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	filter := bson.M{"_id": tokenID, "user_id": userID, "revoked_at": bson.M{"$eq": nil}}
+	res, err := s.tokenCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Authenticate returns the token matching the raw token from a request and
+// records that it was used.
+func (s *APITokenService) Authenticate(ctx context.Context, token string) (*models.APIToken, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	var apiToken models.APIToken
+	filter := bson.M{"token_hash": hashToken(token), "revoked_at": bson.M{"$eq": nil}}
+	if err := s.tokenCollection.FindOne(ctx, filter).Decode(&apiToken); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvalidAPIToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) > lastUsedResolution {
+		_, err := s.tokenCollection.UpdateOne(ctx, bson.M{"_id": apiToken.ID}, bson.M{"$set": bson.M{"last_used_at": now}})
+		if err != nil {
+			return nil, err
+		}
+		apiToken.LastUsedAt = &now
+	}
+	return &apiToken, nil
+}
@@ -8,10 +8,14 @@ import (
 	"errors"
 	"io"
 	"log"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"net/http"
-	"net/url"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,30 +27,206 @@ type AtlassianAPIErr struct {
 }
 
 type AtlassianService struct {
-	config      config.AtlassianConfig
-	userService *UserService
-	httpClient  *http.Client
+	config       config.AtlassianConfig
+	userService  *UserService
+	stateService *OAuthStateService
+	httpClient   *http.Client
+	// refreshLocks holds a *sync.Mutex per user. Atlassian rotates refresh
+	// tokens, so concurrent refreshes would invalidate each other.
+	refreshLocks sync.Map
 }
 
-func NewAtlassianService(c config.AtlassianConfig, us UserService) *AtlassianService {
+func NewAtlassianService(c config.AtlassianConfig, us UserService, ss *OAuthStateService) *AtlassianService {
 	return &AtlassianService{
-		config:      c,
-		userService: &us,
-		httpClient:  &http.Client{},
+		config:       c,
+		userService:  &us,
+		stateService: ss,
+		httpClient:   &http.Client{},
 	}
 }
 
-func (s *AtlassianService) makeAtlassianRequest(method, reqURL string, accessToken string, reqBody interface{}, respTarget interface{}) error {
-	var bodyReader io.Reader
+// tokenRefreshMargin is how long before expiry an access token is refreshed.
+const tokenRefreshMargin = 5 * time.Minute
+
+var (
+	ErrAtlassianReauthRequired = errors.New("atlassian re-authentication required")
+	errAtlassianUnauthorized   = errors.New("Atlassian API returned status: 401 Unauthorized")
+)
+
+type atlassianTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // lifetime of the access token in seconds
+}
+
+func (r atlassianTokenResponse) toIntegration() models.AtlassianIntegration {
+	return models.AtlassianIntegration{
+		Enabled:      true,
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(r.ExpiresIn) * time.Second),
+	}
+}
+
+// requestToken posts to the Atlassian token endpoint and decodes the token
+// response. The returned status code is 0 when no response was received.
+func (s *AtlassianService) requestToken(data map[string]string) (*atlassianTokenResponse, int, error) {
+	jsonBody, err := json.Marshal(data)
+	if err != nil {
+		return nil, 0, errors.New("failed to marshal token request")
+	}
+
+	resp, err := s.httpClient.Post("https://auth.atlassian.com/oauth/token", "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		log.Printf("Error sending token request: %v", err)
+		return nil, 0, errors.New("failed to send token request")
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		log.Printf("Error response from Atlassian token endpoint: Status %d, Body: %s", resp.StatusCode, string(bodyBytes))
+		return nil, resp.StatusCode, errors.New("Atlassian token endpoint returned status: " + resp.Status)
+	}
+
+	var tokenResponse atlassianTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		log.Printf("Error parsing token response: %v", err)
+		return nil, resp.StatusCode, errors.New("failed to parse token response")
+	}
+	if tokenResponse.AccessToken == "" {
+		return nil, resp.StatusCode, errors.New("access token not found in response")
+	}
+	return &tokenResponse, resp.StatusCode, nil
+}
+
+// refreshAccessToken exchanges the stored refresh token for a new access token.
+// Refreshes of a user are serialized, and a token refreshed by another request
+// while waiting is used as is. When Atlassian rejects the refresh token the
+// integration is flagged as requiring re-authentication and
+// ErrAtlassianReauthRequired is returned. Other failures, like rate limiting,
+// are returned without the flag so a later request can try again.
+func (s *AtlassianService) refreshAccessToken(userId string, integration *models.AtlassianIntegration) (string, error) {
+	lock, _ := s.refreshLocks.LoadOrStore(userId, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	stored, err := s.userService.GetAtlassianIntegration(userId)
+	if err != nil {
+		return "", err
+	}
+	if stored.ReauthRequired {
+		return "", ErrAtlassianReauthRequired
+	}
+	refreshedMeanwhile := stored.AccessToken != integration.AccessToken
+	*integration = *stored
+	if refreshedMeanwhile {
+		return integration.AccessToken, nil
+	}
+
+	log.Printf("Refreshing Atlassian access token for user: %s", userId)
+
+	if integration.RefreshToken == "" {
+		return "", s.markReauthRequired(userId, integration)
+	}
+
+	tokenResponse, status, err := s.requestToken(map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     s.config.ClientId,
+		"client_secret": s.config.ClientSecret,
+		"refresh_token": integration.RefreshToken,
+	})
+	if err != nil {
+		// invalid_grant comes as 400, or 401 for a revoked client.
+		if status == http.StatusBadRequest || status == http.StatusUnauthorized {
+			return "", s.markReauthRequired(userId, integration)
+		}
+		return "", err
+	}
+
+	refreshed := tokenResponse.toIntegration()
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = integration.RefreshToken
+	}
+	refreshed.CloudID, refreshed.AccountID = integration.CloudID, integration.AccountID
+	if err := s.userService.UpdateAtlassianIntegration(userId, refreshed); err != nil {
+		log.Printf("Error saving refreshed Atlassian token for user %s: %v", userId, err)
+		return "", err
+	}
+	*integration = refreshed
+	return refreshed.AccessToken, nil
+}
+
+func (s *AtlassianService) markReauthRequired(userId string, integration *models.AtlassianIntegration) error {
+	log.Printf("Atlassian re-authentication required for user: %s", userId)
+	integration.ReauthRequired = true
+	if err := s.userService.UpdateAtlassianIntegration(userId, *integration); err != nil {
+		log.Printf("Error flagging Atlassian integration for user %s: %v", userId, err)
+	}
+	return ErrAtlassianReauthRequired
+}
+
+// getAccessToken returns a usable access token for the user, refreshing it
+// first when it is about to expire or when forceRefresh is set.
+func (s *AtlassianService) getAccessToken(userId string, forceRefresh bool) (string, error) {
+	integration, err := s.userService.GetAtlassianIntegration(userId)
+	if err != nil {
+		log.Printf("Error fetching Atlassian integration for user %s: %v", userId, err)
+		return "", err
+	}
+	if !integration.Enabled {
+		return "", errors.New("atlassian integration is not enabled for user: " + userId)
+	}
+	if integration.ReauthRequired {
+		return "", ErrAtlassianReauthRequired
+	}
+	if integration.AccessToken == "" {
+		return "", errors.New("access token is empty for user: " + userId)
+	}
+
+	if forceRefresh || (!integration.ExpiresAt.IsZero() && time.Until(integration.ExpiresAt) < tokenRefreshMargin) {
+		return s.refreshAccessToken(userId, integration)
+	}
+	return integration.AccessToken, nil
+}
+
+// makeAtlassianRequest performs an authenticated request on behalf of the user.
+// A 401 response triggers a single token refresh and retry.
+func (s *AtlassianService) makeAtlassianRequest(method, reqURL string, userId string, reqBody interface{}, respTarget interface{}) error {
+	var jsonBody []byte
 	if reqBody != nil {
-		jsonBody, err := json.Marshal(reqBody)
+		var err error
+		jsonBody, err = json.Marshal(reqBody)
 		if err != nil {
 			log.Printf("Error marshaling request body: %v", err)
 			return errors.New("failed to marshal request body")
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
-	} else {
-		bodyReader = nil
+	}
+
+	accessToken, err := s.getAccessToken(userId, false)
+	if err != nil {
+		return err
+	}
+
+	err = s.sendAtlassianRequest(method, reqURL, accessToken, jsonBody, respTarget)
+	if errors.Is(err, errAtlassianUnauthorized) {
+		accessToken, err = s.getAccessToken(userId, true)
+		if err != nil {
+			return err
+		}
+		err = s.sendAtlassianRequest(method, reqURL, accessToken, jsonBody, respTarget)
+	}
+	return err
+}
+
+func (s *AtlassianService) sendAtlassianRequest(method, reqURL string, accessToken string, jsonBody []byte, respTarget interface{}) error {
+	var bodyReader io.Reader
+	if jsonBody != nil {
+		bodyReader = bytes.NewReader(jsonBody)
 	}
 
 	req, err := http.NewRequest(method, reqURL, bodyReader)
@@ -59,7 +239,7 @@ func (s *AtlassianService) makeAtlassianRequest(method, reqURL string, accessTok
 		req.Header.Set("Authorization", "Bearer "+accessToken)
 	}
 	req.Header.Set("Accept", "application/json")
-	if reqBody != nil {
+	if jsonBody != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
@@ -74,6 +254,11 @@ func (s *AtlassianService) makeAtlassianRequest(method, reqURL string, accessTok
 		}
 	}()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		log.Printf("Atlassian API rejected access token for %s %s", method, reqURL)
+		return errAtlassianUnauthorized
+	}
+
 	if resp.StatusCode >= 400 {
 		var apiErr AtlassianAPIErr
 		bodyBytes, _ := io.ReadAll(resp.Body)
@@ -107,16 +292,23 @@ func (s *AtlassianService) GetOAuthURL(c *gin.Context) {
 
 	userId := c.GetString("user_id")
 
+	state, err := s.stateService.Create(c, userId, s.Type())
+	if err != nil {
+		log.Printf("Error creating OAuth state for user %s: %v", userId, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start Atlassian authentication"})
+		return
+	}
+
 	oauthURL := "https://auth.atlassian.com/authorize?" +
 		"audience=" + s.config.Audience +
 		"&client_id=" + s.config.ClientId +
 		"&scope=" + s.config.Scope +
 		"&redirect_uri=" + s.config.CallbackUrl +
-		"&state=" + userId +
+		"&state=" + url.QueryEscape(state) +
 		"&response_type=code" +
 		"&prompt=consent"
 
-	log.Println("Generated OAuth URL:", oauthURL)
+	log.Printf("Generated OAuth URL for user: %s", userId)
 
 	c.JSON(http.StatusOK, gin.H{
 		"oauth_url": oauthURL,
@@ -127,107 +319,70 @@ func (s *AtlassianService) HandleOAuthCallback(c *gin.Context) {
 	log.Println("Handling OAuth callback from Atlassian")
 
 	code := c.Query("code")
-	userId := c.Query("state")
+	state := c.Query("state")
 
-	if code == "" || userId == "" {
-		log.Println("Missing code or state in OAuth callback")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing code or state"})
+	if state == "" {
+		log.Println("Missing state in OAuth callback")
+		renderOAuthResult(c, http.StatusBadRequest, false, "Authentication failed", "The request is missing its state. Please start the authentication again from TimeTrack.")
 		return
 	}
 
-	_, err := s.userService.GetUserByID(c, userId)
+	// The state is consumed before anything else, so it cannot be replayed.
+	userId, err := s.stateService.Consume(c, state, s.Type())
 	if err != nil {
-		log.Printf("User not found for OAuth callback (userId: %s): %v", userId, err)
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		log.Printf("Rejected OAuth callback with invalid state: %v", err)
+		renderOAuthResult(c, http.StatusBadRequest, false, "Authentication failed", "This authentication link is invalid, expired or was already used. Please start the authentication again from TimeTrack.")
 		return
 	}
 
-	oauthTokenUrl := "https://auth.atlassian.com/oauth/token"
-	data := url.Values{}
-	data.Set("grant_type", "authorization_code")
-	data.Set("client_id", s.config.ClientId)
-	data.Set("client_secret", s.config.ClientSecret)
-	data.Set("code", code)
-	data.Set("redirect_uri", s.config.CallbackUrl)
-
-	req, err := http.NewRequest(http.MethodPost, oauthTokenUrl, strings.NewReader(data.Encode()))
-	if err != nil {
-		log.Printf("Error creating token exchange request: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token request"})
+	if oauthErr := c.Query("error"); oauthErr != "" {
+		log.Printf("Atlassian authentication was not completed for user %s: %s", userId, oauthErr)
+		renderOAuthResult(c, http.StatusBadRequest, false, "Authentication cancelled", "Atlassian did not grant access: "+c.Query("error_description"))
 		return
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending token exchange request: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send token request"})
-		return
-	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			log.Printf("error closing response body: %v", cerr)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		log.Printf("Error response from Atlassian token endpoint: Status %d, Body: %s", resp.StatusCode, string(bodyBytes))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to exchange code for token"})
+	if code == "" {
+		log.Println("Missing code in OAuth callback")
+		renderOAuthResult(c, http.StatusBadRequest, false, "Authentication failed", "Atlassian did not send an authorization code. Please try again.")
 		return
 	}
 
-	var tokenResponse map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
-		log.Printf("Error parsing token response: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse token response"})
+	_, err = s.userService.GetUserByID(c, userId)
+	if err != nil {
+		log.Printf("User not found for OAuth callback (userId: %s): %v", userId, err)
+		renderOAuthResult(c, http.StatusNotFound, false, "Authentication failed", "The TimeTrack user that started the authentication no longer exists.")
 		return
 	}
 
-	accessToken, ok := tokenResponse["access_token"].(string)
-	if !ok {
-		log.Println("Access token not found in response or not a string type assertion failed")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Access token not found in response"})
+	tokenResponse, _, err := s.requestToken(map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     s.config.ClientId,
+		"client_secret": s.config.ClientSecret,
+		"code":          code,
+		"redirect_uri":  s.config.CallbackUrl,
+	})
+	if err != nil {
+		log.Printf("Error exchanging code for token: %v", err)
+		renderOAuthResult(c, http.StatusBadGateway, false, "Authentication failed", "TimeTrack could not exchange the authorization code with Atlassian. Please try again.")
 		return
 	}
 
-	err = s.userService.UpdateIntegration(c, userId, "atlassian", models.UserIntegration{
-		Atlassian: models.AtlassianIntegration{
-			Enabled:     true,
-			AccessToken: accessToken,
-		},
-	})
+	err = s.userService.UpdateAtlassianIntegration(userId, tokenResponse.toIntegration())
 	if err != nil {
 		log.Printf("Error updating Atlassian integration for user %s: %v", userId, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save Atlassian integration"})
+		renderOAuthResult(c, http.StatusInternalServerError, false, "Authentication failed", "TimeTrack could not save the Atlassian integration. Please try again.")
 		return
 	}
 
 	log.Printf("Authentication with Atlassian successful for user: %s", userId)
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Authentication with Atlassian successful. You can now safely close this window.",
-	})
+	renderOAuthResult(c, http.StatusOK, true, "Authentication successful", "TimeTrack is now connected to your Atlassian account.")
 }
 
 func (s *AtlassianService) GetCloudId(userId string) (string, error) {
 	log.Printf("Fetching cloud ID from Atlassian for user: %s", userId)
 
-	atlassianIntegration, err := s.userService.GetAtlassianIntegration(userId)
-	if err != nil {
-		log.Printf("Error fetching Atlassian integration for user %s: %v", userId, err)
-		return "", err
-	}
-	if !atlassianIntegration.Enabled {
-		return "", errors.New("atlassian integration not enabled for user: " + userId)
-	}
-	if atlassianIntegration.AccessToken == "" {
-		return "", errors.New("atlassian access token is empty for user: " + userId)
-	}
-
 	cloudIdUrl := "https://api.atlassian.com/oauth/token/accessible-resources"
 	var resources []map[string]interface{}
-	err = s.makeAtlassianRequest(http.MethodGet, cloudIdUrl, atlassianIntegration.AccessToken, nil, &resources)
+	err := s.makeAtlassianRequest(http.MethodGet, cloudIdUrl, userId, nil, &resources)
 	if err != nil {
 		log.Printf("Error making request to get accessible resources for user %s: %v", userId, err)
 		return "", err
@@ -251,18 +406,6 @@ func (s *AtlassianService) GetCloudId(userId string) (string, error) {
 func (s *AtlassianService) CheckIfJiraTicketExists(userId string, ticketId string) error {
 	log.Printf("Checking Jira ticket existence for user: %s, ticket: %s", userId, ticketId)
 
-	atlassianIntegration, err := s.userService.GetAtlassianIntegration(userId)
-	if err != nil {
-		log.Printf("Error fetching Atlassian integration for user %s: %v", userId, err)
-		return err
-	}
-	if !atlassianIntegration.Enabled {
-		return errors.New("atlassian integration is not enabled for user: " + userId)
-	}
-	if atlassianIntegration.AccessToken == "" {
-		return errors.New("access token is empty for user: " + userId)
-	}
-
 	cloudId, err := s.GetCloudId(userId)
 	if err != nil {
 		log.Printf("Error fetching cloud ID for user %s: %v", userId, err)
@@ -274,7 +417,7 @@ func (s *AtlassianService) CheckIfJiraTicketExists(userId string, ticketId strin
 
 	jiraUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/3/issue/" + ticketId
 	var ticketInfo map[string]interface{}
-	err = s.makeAtlassianRequest(http.MethodGet, jiraUrl, atlassianIntegration.AccessToken, nil, &ticketInfo)
+	err = s.makeAtlassianRequest(http.MethodGet, jiraUrl, userId, nil, &ticketInfo)
 	if err != nil {
 		log.Printf("Error checking Jira ticket %s for user %s: %v", ticketId, userId, err)
 		if strings.Contains(err.Error(), "Status: 404 Not Found") {
@@ -292,22 +435,113 @@ func (s *AtlassianService) CheckIfJiraTicketExists(userId string, ticketId strin
 	return nil
 }
 
-func (s *AtlassianService) AddTimeEntryToJira(entry *models.TimeEntry, ticketId string) (string, error) {
-	log.Printf("Adding time entry to Jira ticket: %s for owner: %s", ticketId, entry.OwnerID)
+// jiraIssueResponse is the part of a Jira issue that is used by TimeTrack.
+type jiraIssueResponse struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
 
-	userId := entry.OwnerID
+func (r jiraIssueResponse) toIssue() models.Issue {
+	return models.Issue{
+		ID:      r.ID,
+		Key:     r.Key,
+		Summary: r.Fields.Summary,
+		Status:  r.Fields.Status.Name,
+	}
+}
 
-	atlassianIntegration, err := s.userService.GetAtlassianIntegration(userId)
+// jiraIssueKeyPattern matches a query that is an issue key on its own.
+var jiraIssueKeyPattern = regexp.MustCompile(`(?i)^[A-Z][A-Z0-9_]+-[0-9]+$`)
+
+// GetJiraIssue returns the issue with the given key or ID.
+func (s *AtlassianService) GetJiraIssue(userId string, issueKey string) (*models.Issue, error) {
+	cloudId, err := s.GetCloudId(userId)
 	if err != nil {
-		log.Printf("Error fetching Atlassian integration for user %s: %v", userId, err)
-		return "", err
+		return nil, err
 	}
-	if !atlassianIntegration.Enabled {
-		return "", errors.New("atlassian integration is not enabled for user: " + userId)
+
+	issue, err := s.getJiraIssue(userId, cloudId, issueKey)
+	if err != nil {
+		if isAtlassianNotFound(err) {
+			return nil, errors.New("jira ticket not found: " + issueKey)
+		}
+		return nil, err
 	}
-	if atlassianIntegration.AccessToken == "" {
-		return "", errors.New("access token is empty for user: " + userId)
+	return issue, nil
+}
+
+func (s *AtlassianService) getJiraIssue(userId string, cloudId string, issueKey string) (*models.Issue, error) {
+	jiraUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/3/issue/" + url.PathEscape(issueKey) + "?fields=summary,status"
+	var issue jiraIssueResponse
+	if err := s.makeAtlassianRequest(http.MethodGet, jiraUrl, userId, nil, &issue); err != nil {
+		return nil, err
+	}
+	result := issue.toIssue()
+	return &result, nil
+}
+
+// SearchJiraIssues runs a text search over the issues the user can access,
+// most recently updated first. A query that is an issue key also matches that
+// issue directly. The issue is looked up on its own, since Jira rejects the
+// whole search when a key in the JQL does not exist.
+func (s *AtlassianService) SearchJiraIssues(userId string, query string, maxResults int) ([]models.Issue, error) {
+	log.Printf("Searching Jira issues for user: %s, query: %s", userId, query)
+
+	cloudId, err := s.GetCloudId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.TrimSpace(query))
+	jql := `text ~ "` + escaped + `*" ORDER BY updated DESC`
+
+	params := url.Values{}
+	params.Set("jql", jql)
+	params.Set("fields", "summary,status")
+	params.Set("maxResults", strconv.Itoa(maxResults))
+	jiraUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/3/search?" + params.Encode()
+
+	var response struct {
+		Issues []jiraIssueResponse `json:"issues"`
+	}
+	if err := s.makeAtlassianRequest(http.MethodGet, jiraUrl, userId, nil, &response); err != nil {
+		log.Printf("Error searching Jira issues for user %s: %v", userId, err)
+		return nil, err
+	}
+
+	issues := make([]models.Issue, 0, len(response.Issues)+1)
+	if jiraIssueKeyPattern.MatchString(query) {
+		issue, err := s.getJiraIssue(userId, cloudId, strings.ToUpper(strings.TrimSpace(query)))
+		if err != nil && !isAtlassianNotFound(err) {
+			log.Printf("Error fetching Jira issue %s for user %s: %v", query, userId, err)
+			return nil, err
+		}
+		if issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	for _, issue := range response.Issues {
+		if len(issues) > 0 && issues[0].ID == issue.ID {
+			continue
+		}
+		issues = append(issues, issue.toIssue())
+	}
+	if len(issues) > maxResults {
+		issues = issues[:maxResults]
 	}
+	return issues, nil
+}
+
+func (s *AtlassianService) AddTimeEntryToJira(entry *models.TimeEntry, ticketId string) (string, error) {
+	log.Printf("Adding time entry to Jira ticket: %s for owner: %s", ticketId, entry.OwnerID)
+
+	userId := entry.OwnerID
 
 	cloudId, err := s.GetCloudId(userId)
 	if err != nil {
@@ -322,11 +556,12 @@ func (s *AtlassianService) AddTimeEntryToJira(entry *models.TimeEntry, ticketId
 
 	reqBody := map[string]interface{}{
 		"comment":          entry.Note,
+		"started":          entry.Period.Started.Format(jiraTimeFormat),
 		"timeSpentSeconds": entry.Period.Duration,
 	}
 
 	var worklogResponse map[string]interface{}
-	err = s.makeAtlassianRequest(http.MethodPost, jiraUrl, atlassianIntegration.AccessToken, reqBody, &worklogResponse)
+	err = s.makeAtlassianRequest(http.MethodPost, jiraUrl, userId, reqBody, &worklogResponse)
 	if err != nil {
 		log.Printf("Error adding time entry to Jira ticket %s for user %s: %v", ticketId, userId, err)
 		return "", errors.New("failed to add time entry to Jira: " + err.Error())
@@ -347,18 +582,6 @@ func (s *AtlassianService) UpdateTimeEntryInJira(entry *models.TimeEntry, ticket
 
 	userId := entry.OwnerID
 
-	atlassianIntegration, err := s.userService.GetAtlassianIntegration(userId)
-	if err != nil {
-		log.Printf("Error fetching Atlassian integration for user %s: %v", userId, err)
-		return "", err
-	}
-	if !atlassianIntegration.Enabled {
-		return "", errors.New("atlassian integration is not enabled for user: " + userId)
-	}
-	if atlassianIntegration.AccessToken == "" {
-		return "", errors.New("access token is empty for user: " + userId)
-	}
-
 	cloudId, err := s.GetCloudId(userId)
 	if err != nil {
 		log.Printf("Error fetching cloud ID for user %s: %v", userId, err)
@@ -371,11 +594,12 @@ func (s *AtlassianService) UpdateTimeEntryInJira(entry *models.TimeEntry, ticket
 
 	reqBody := map[string]interface{}{
 		"comment":          entry.Note,
+		"started":          entry.Period.Started.Format(jiraTimeFormat),
 		"timeSpentSeconds": entry.Period.Duration,
 	}
 
 	var worklogResponse map[string]interface{}
-	err = s.makeAtlassianRequest(http.MethodPut, jiraUrl, atlassianIntegration.AccessToken, reqBody, &worklogResponse)
+	err = s.makeAtlassianRequest(http.MethodPut, jiraUrl, userId, reqBody, &worklogResponse)
 	if err != nil {
 		log.Printf("Error updating time entry %s in Jira ticket %s: %v", worklogId, ticketId, err)
 		return "", errors.New("failed to update time entry in Jira: " + err.Error())
@@ -394,18 +618,6 @@ func (s *AtlassianService) UpdateTimeEntryInJira(entry *models.TimeEntry, ticket
 func (s *AtlassianService) RemoveTimeEntryFromJira(userId string, ticketId string, worklogId string) error {
 	log.Printf("Removing time entry %s from Jira ticket: %s for owner: %s", worklogId, ticketId, userId)
 
-	atlassianIntegration, err := s.userService.GetAtlassianIntegration(userId)
-	if err != nil {
-		log.Printf("Error fetching Atlassian integration for user %s: %v", userId, err)
-		return err
-	}
-	if !atlassianIntegration.Enabled {
-		return errors.New("atlassian integration is not enabled for user: " + userId)
-	}
-	if atlassianIntegration.AccessToken == "" {
-		return errors.New("access token is empty for user: " + userId)
-	}
-
 	cloudId, err := s.GetCloudId(userId)
 	if err != nil {
 		log.Printf("Error fetching cloud ID for user %s: %v", userId, err)
@@ -417,7 +629,7 @@ func (s *AtlassianService) RemoveTimeEntryFromJira(userId string, ticketId strin
 
 	jiraUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/2/issue/" + ticketId + "/worklog/" + worklogId
 
-	err = s.makeAtlassianRequest(http.MethodDelete, jiraUrl, atlassianIntegration.AccessToken, nil, nil)
+	err = s.makeAtlassianRequest(http.MethodDelete, jiraUrl, userId, nil, nil)
 	if err != nil {
 		log.Printf("Error removing time entry %s from Jira ticket %s: %v", worklogId, ticketId, err)
 		return errors.New("failed to remove time entry from Jira: " + err.Error())
@@ -426,3 +638,319 @@ func (s *AtlassianService) RemoveTimeEntryFromJira(userId string, ticketId strin
 	log.Printf("Worklog ID %s successfully removed from Jira ticket %s", worklogId, ticketId)
 	return nil
 }
+
+// AtlassianService implements IntegrationProvider for Jira.
+var (
+	_ IntegrationProvider = (*AtlassianService)(nil)
+	_ WorklogLister       = (*AtlassianService)(nil)
+	_ WorklogFetcher      = (*AtlassianService)(nil)
+)
+
+func (s *AtlassianService) Type() string {
+	return "jira"
+}
+
+func (s *AtlassianService) VerifyIssue(userID string, issueKey string) error {
+	return s.CheckIfJiraTicketExists(userID, issueKey)
+}
+
+func (s *AtlassianService) LinkProject(userID string, issueKey string) (models.IntegrationInfo, error) {
+	issue, err := s.GetJiraIssue(userID, issueKey)
+	if err != nil {
+		return models.IntegrationInfo{}, err
+	}
+	return models.IntegrationInfo{
+		Type:       s.Type(),
+		Key:        issue.Key,
+		ExternalID: issue.ID,
+	}, nil
+}
+
+func (s *AtlassianService) CreateWorklog(entry *models.TimeEntry, issueID string) (string, error) {
+	return s.AddTimeEntryToJira(entry, issueID)
+}
+
+func (s *AtlassianService) UpdateWorklog(entry *models.TimeEntry, issueID string, worklogID string) (string, error) {
+	return s.UpdateTimeEntryInJira(entry, issueID, worklogID)
+}
+
+func (s *AtlassianService) DeleteWorklog(userID string, issueID string, worklogID string) error {
+	return s.RemoveTimeEntryFromJira(userID, issueID, worklogID)
+}
+
+// jiraTimeFormat is the timestamp format of the Jira REST API.
+const jiraTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+type jiraWorklogResponse struct {
+	ID      string `json:"id"`
+	IssueID string `json:"issueId"`
+	Author  struct {
+		AccountID string `json:"accountId"`
+	} `json:"author"`
+	Started          string          `json:"started"`
+	TimeSpentSeconds int             `json:"timeSpentSeconds"`
+	Comment          json.RawMessage `json:"comment"` // a string in API v2, a document in API v3
+}
+
+func (r jiraWorklogResponse) toWorklog(issueID string) models.Worklog {
+	started, err := time.Parse(jiraTimeFormat, r.Started)
+	if err != nil {
+		log.Printf("Error parsing start %q of Jira worklog %s: %v", r.Started, r.ID, err)
+	}
+	return models.Worklog{
+		ID:       r.ID,
+		IssueID:  issueID,
+		Started:  started,
+		Duration: r.TimeSpentSeconds,
+		Comment:  jiraCommentText(r.Comment),
+	}
+}
+
+// adfNode is a node of an Atlassian Document Format document.
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+func (n adfNode) plainText() string {
+	switch n.Type {
+	case "text":
+		return n.Text
+	case "hardBreak":
+		return "\n"
+	}
+	parts := make([]string, 0, len(n.Content))
+	for _, child := range n.Content {
+		parts = append(parts, child.plainText())
+	}
+	if n.Type == "doc" || n.Type == "bulletList" || n.Type == "orderedList" {
+		return strings.Join(parts, "\n")
+	}
+	return strings.Join(parts, "")
+}
+
+// jiraCommentText returns the text of a worklog comment from either API version.
+func jiraCommentText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.plainText())
+}
+
+func isAtlassianNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "404 Not Found")
+}
+
+// getAccountId returns the Atlassian account ID of the user, which is the
+// author of the worklogs they create.
+func (s *AtlassianService) getAccountId(userId string, cloudId string) (string, error) {
+	var myself struct {
+		AccountID string `json:"accountId"`
+	}
+	err := s.makeAtlassianRequest(http.MethodGet, "https://api.atlassian.com/ex/jira/"+cloudId+"/rest/api/3/myself", userId, nil, &myself)
+	if err != nil {
+		return "", err
+	}
+	if myself.AccountID == "" {
+		return "", errors.New("account ID not found in Jira response")
+	}
+	return myself.AccountID, nil
+}
+
+// getJiraSite returns the cloud ID of the user's Jira site and their account ID
+// there. Both are looked up once and cached on the integration, as every
+// worklog read needs them. Connecting the account again clears the cache.
+func (s *AtlassianService) getJiraSite(userId string) (string, string, error) {
+	integration, err := s.userService.GetAtlassianIntegration(userId)
+	if err != nil {
+		return "", "", err
+	}
+	if integration.CloudID != "" && integration.AccountID != "" {
+		return integration.CloudID, integration.AccountID, nil
+	}
+
+	cloudId, err := s.GetCloudId(userId)
+	if err != nil {
+		return "", "", err
+	}
+	accountId, err := s.getAccountId(userId, cloudId)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.userService.SetAtlassianSite(userId, cloudId, accountId); err != nil {
+		log.Printf("Error caching Jira site of user %s: %v", userId, err)
+	}
+	return cloudId, accountId, nil
+}
+
+// ListWorklogs pages through the worklogs of each issue and keeps the ones the
+// user authored.
+func (s *AtlassianService) ListWorklogs(userId string, issueIds []string, from, to time.Time) ([]models.Worklog, error) {
+	cloudId, accountId, err := s.getJiraSite(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	worklogs := []models.Worklog{}
+	for _, issueId := range issueIds {
+		for startAt := 0; ; {
+			params := url.Values{}
+			params.Set("startedAfter", strconv.FormatInt(from.UnixMilli(), 10))
+			params.Set("startedBefore", strconv.FormatInt(to.UnixMilli(), 10))
+			params.Set("startAt", strconv.Itoa(startAt))
+			params.Set("maxResults", "1000")
+			jiraUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/2/issue/" + url.PathEscape(issueId) + "/worklog?" + params.Encode()
+
+			var page struct {
+				StartAt  int                   `json:"startAt"`
+				Total    int                   `json:"total"`
+				Worklogs []jiraWorklogResponse `json:"worklogs"`
+			}
+			if err := s.makeAtlassianRequest(http.MethodGet, jiraUrl, userId, nil, &page); err != nil {
+				if isAtlassianNotFound(err) {
+					log.Printf("Jira issue %s of user %s not found, skipping its worklogs", issueId, userId)
+					break
+				}
+				return nil, err
+			}
+			for _, worklog := range page.Worklogs {
+				if worklog.Author.AccountID == accountId {
+					worklogs = append(worklogs, worklog.toWorklog(issueId))
+				}
+			}
+			startAt += len(page.Worklogs)
+			if len(page.Worklogs) == 0 || startAt >= page.Total {
+				break
+			}
+		}
+	}
+	return worklogs, nil
+}
+
+// GetWorklog returns the worklog when the user is its author. Worklogs of
+// other users are reported as ErrWorklogNotFound.
+func (s *AtlassianService) GetWorklog(userId string, issueId string, worklogId string) (*models.Worklog, error) {
+	cloudId, accountId, err := s.getJiraSite(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	jiraUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/2/issue/" + url.PathEscape(issueId) + "/worklog/" + url.PathEscape(worklogId)
+	var worklog jiraWorklogResponse
+	if err := s.makeAtlassianRequest(http.MethodGet, jiraUrl, userId, nil, &worklog); err != nil {
+		if isAtlassianNotFound(err) {
+			return nil, ErrWorklogNotFound
+		}
+		return nil, err
+	}
+	if worklog.Author.AccountID != accountId {
+		return nil, ErrWorklogNotFound
+	}
+	result := worklog.toWorklog(issueId)
+	return &result, nil
+}
+
+// FetchWorklogs finds the worklogs through the feed of updated worklogs, which
+// covers every issue. The feed starts at from, so worklogs last changed before
+// the range are not found.
+func (s *AtlassianService) FetchWorklogs(userId string, from, to time.Time) ([]models.Worklog, error) {
+	log.Printf("Fetching Jira worklogs for user: %s, from %s to %s", userId, from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+	cloudId, accountId, err := s.getJiraSite(userId)
+	if err != nil {
+		return nil, err
+	}
+	baseUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/3"
+
+	var worklogIds []int64
+	for since := from.UnixMilli(); ; {
+		var page struct {
+			Values []struct {
+				WorklogID int64 `json:"worklogId"`
+			} `json:"values"`
+			Until    int64 `json:"until"`
+			LastPage bool  `json:"lastPage"`
+		}
+		if err := s.makeAtlassianRequest(http.MethodGet, baseUrl+"/worklog/updated?since="+strconv.FormatInt(since, 10), userId, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, value := range page.Values {
+			worklogIds = append(worklogIds, value.WorklogID)
+		}
+		if page.LastPage || len(page.Values) == 0 || page.Until <= since {
+			break
+		}
+		since = page.Until
+	}
+
+	worklogs := []models.Worklog{}
+	issueIds := make(map[string]bool)
+	// The list endpoint accepts at most 1000 IDs per request.
+	for start := 0; start < len(worklogIds); start += 1000 {
+		end := min(start+1000, len(worklogIds))
+		var batch []jiraWorklogResponse
+		reqBody := map[string]interface{}{"ids": worklogIds[start:end]}
+		if err := s.makeAtlassianRequest(http.MethodPost, baseUrl+"/worklog/list", userId, reqBody, &batch); err != nil {
+			return nil, err
+		}
+		for _, response := range batch {
+			if response.Author.AccountID != accountId {
+				continue
+			}
+			worklog := response.toWorklog(response.IssueID)
+			if worklog.Started.Before(from) || worklog.Started.After(to) {
+				continue
+			}
+			worklogs = append(worklogs, worklog)
+			issueIds[response.IssueID] = true
+		}
+	}
+
+	keys, err := s.getJiraIssueKeys(userId, cloudId, issueIds)
+	if err != nil {
+		return nil, err
+	}
+	found := worklogs[:0]
+	for _, worklog := range worklogs {
+		if worklog.IssueKey = keys[worklog.IssueID]; worklog.IssueKey != "" {
+			found = append(found, worklog)
+		}
+	}
+	log.Printf("Found %d Jira worklogs on %d issues for user %s", len(found), len(keys), userId)
+	return found, nil
+}
+
+// getJiraIssueKeys maps issue IDs to issue keys. Issues the user can no longer
+// see are left out.
+func (s *AtlassianService) getJiraIssueKeys(userId string, cloudId string, issueIds map[string]bool) (map[string]string, error) {
+	ids := make([]string, 0, len(issueIds))
+	for id := range issueIds {
+		ids = append(ids, id)
+	}
+
+	keys := make(map[string]string, len(ids))
+	for start := 0; start < len(ids); start += 100 {
+		end := min(start+100, len(ids))
+		params := url.Values{}
+		params.Set("jql", "id in ("+strings.Join(ids[start:end], ",")+")")
+		params.Set("fields", "summary")
+		params.Set("maxResults", "100")
+		var response struct {
+			Issues []jiraIssueResponse `json:"issues"`
+		}
+		jiraUrl := "https://api.atlassian.com/ex/jira/" + cloudId + "/rest/api/3/search?" + params.Encode()
+		if err := s.makeAtlassianRequest(http.MethodGet, jiraUrl, userId, nil, &response); err != nil {
+			return nil, err
+		}
+		for _, issue := range response.Issues {
+			keys[issue.ID] = issue.Key
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditService struct {
+	auditCollection *mongo.Collection
+}
+
+func NewAuditService(db *mongo.Database) *AuditService {
+	return &AuditService{auditCollection: db.Collection("audit_events")}
+}
+
+func (s *AuditService) Record(ctx context.Context, event *models.AuditEvent) error {
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now()
+	_, err := s.auditCollection.InsertOne(ctx, event)
+	return err
+}
+
+// GetWorkspaceEvents returns the latest events of the workspace, newest first.
+func (s *AuditService) GetWorkspaceEvents(ctx context.Context, workspaceID string, limit int64) ([]models.AuditEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := s.auditCollection.Find(ctx, bson.M{"workspace_id": workspaceID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	events := []models.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
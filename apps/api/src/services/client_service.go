@@ -0,0 +1,85 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrClientInUse = errors.New("client still has projects")
+
+type ClientService struct {
+	clientCollection *mongo.Collection
+	projectService   *ProjectService
+}
+
+func NewClientService(db *mongo.Database, ps *ProjectService) *ClientService {
+	return &ClientService{
+		clientCollection: db.Collection("clients"),
+		projectService:   ps,
+	}
+}
+
+func (s *ClientService) CreateClient(ctx context.Context, client *models.Client) error {
+	client.ID = uuid.New().String()
+	client.CreatedAt = time.Now()
+	client.UpdatedAt = client.CreatedAt
+	client.DeletedAt = nil
+	_, err := s.clientCollection.InsertOne(ctx, client)
+	return err
+}
+
+// GetClients returns the clients of the workspace, or the personal clients of
+// the owner when workspaceID is empty, by name.
+func (s *ClientService) GetClients(ctx context.Context, ownerID string, workspaceID string) ([]models.Client, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := s.clientCollection.Find(ctx, scopeFilter(ownerID, workspaceID), opts)
+	if err != nil {
+		return nil, err
+	}
+	clients := []models.Client{}
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// GetClient returns the client in the workspace, or the personal client of the
+// owner when workspaceID is empty, or mongo.ErrNoDocuments.
+func (s *ClientService) GetClient(ctx context.Context, id string, ownerID string, workspaceID string) (*models.Client, error) {
+	filter := scopeFilter(ownerID, workspaceID)
+	filter["_id"] = id
+	var client models.Client
+	if err := s.clientCollection.FindOne(ctx, filter).Decode(&client); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (s *ClientService) RenameClient(ctx context.Context, client *models.Client, name string) error {
+	client.Name = name
+	client.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{"name": client.Name, "updated_at": client.UpdatedAt}}
+	_, err := s.clientCollection.UpdateOne(ctx, bson.M{"_id": client.ID}, update)
+	return err
+}
+
+// DeleteClient deletes the client, or returns ErrClientInUse while projects
+// still bill to it.
+func (s *ClientService) DeleteClient(ctx context.Context, id string) error {
+	inUse, err := s.projectService.HasClientProjects(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrClientInUse
+	}
+	_, err = s.clientCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	return err
+}
@@ -0,0 +1,367 @@
+package services
+
+import (
+	"TimeTrack-api/src/config"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitLab has no worklogs, so time is reported with "/spend" quick actions in
+// issue notes. Every reported entry is one note on the issue and the worklog ID
+// stored on the entry is "<note id>:<seconds spent>", which lets updates and
+// deletes correct the spent time by the difference.
+
+var (
+	ErrGitLabNotConnected  = errors.New("gitlab integration is not connected")
+	ErrGitLabURLNotAllowed = errors.New("gitlab URL is not allowed")
+	errGitLabNotFound      = errors.New("GitLab API returned status: 404 Not Found")
+)
+
+type gitLabAPIErr struct {
+	Message interface{} `json:"message"`
+	Error   string      `json:"error"`
+}
+
+type GitLabService struct {
+	config      config.GitLabConfig
+	userService *UserService
+	httpClient  *http.Client
+}
+
+func NewGitLabService(c config.GitLabConfig, us *UserService) *GitLabService {
+	return &GitLabService{
+		config:      c,
+		userService: us,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GitLabService implements IntegrationProvider for GitLab issues.
+var _ IntegrationProvider = (*GitLabService)(nil)
+
+func (s *GitLabService) Type() string {
+	return "gitlab"
+}
+
+// Connect verifies the personal access token against the GitLab instance and
+// stores it on the user.
+func (s *GitLabService) Connect(userID string, input *dtos.ConnectGitLabInput) error {
+	baseURL, err := gitLabBaseURL(s.config, input.BaseURL)
+	if err != nil {
+		return err
+	}
+	integration := models.GitLabIntegration{
+		Enabled:     true,
+		BaseURL:     baseURL,
+		AccessToken: input.AccessToken,
+	}
+	if err := s.makeGitLabRequest(&integration, http.MethodGet, "/user", nil, nil); err != nil {
+		log.Printf("Error verifying GitLab token for user %s: %v", userID, err)
+		return err
+	}
+	return s.userService.UpdateGitLabIntegration(userID, integration)
+}
+
+func (s *GitLabService) Disconnect(userID string) error {
+	return s.userService.UpdateGitLabIntegration(userID, models.GitLabIntegration{})
+}
+
+func (s *GitLabService) VerifyIssue(userID string, issueKey string) error {
+	integration, err := s.getIntegration(userID)
+	if err != nil {
+		return err
+	}
+	path, err := gitLabIssuePath(issueKey)
+	if err != nil {
+		return err
+	}
+
+	var issue struct {
+		IID int `json:"iid"`
+	}
+	if err := s.makeGitLabRequest(integration, http.MethodGet, path, nil, &issue); err != nil {
+		if errors.Is(err, errGitLabNotFound) {
+			return errors.New("gitlab issue not found: " + issueKey)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *GitLabService) LinkProject(userID string, issueKey string) (models.IntegrationInfo, error) {
+	if err := s.VerifyIssue(userID, issueKey); err != nil {
+		return models.IntegrationInfo{}, err
+	}
+	return models.IntegrationInfo{
+		Type:       s.Type(),
+		Key:        issueKey,
+		ExternalID: issueKey,
+	}, nil
+}
+
+func (s *GitLabService) CreateWorklog(entry *models.TimeEntry, issueID string) (string, error) {
+	integration, err := s.getIntegration(entry.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	return s.createWorklog(integration, entry, issueID)
+}
+
+func (s *GitLabService) UpdateWorklog(entry *models.TimeEntry, issueID string, worklogID string) (string, error) {
+	integration, err := s.getIntegration(entry.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	return s.updateWorklog(integration, entry, issueID, worklogID)
+}
+
+func (s *GitLabService) DeleteWorklog(userID string, issueID string, worklogID string) error {
+	integration, err := s.getIntegration(userID)
+	if err != nil {
+		return err
+	}
+	return s.deleteWorklog(integration, issueID, worklogID)
+}
+
+func (s *GitLabService) createWorklog(integration *models.GitLabIntegration, entry *models.TimeEntry, issueID string) (string, error) {
+	path, err := gitLabIssuePath(issueID)
+	if err != nil {
+		return "", err
+	}
+
+	spent := gitLabSpentSeconds(entry.Period.Duration)
+	body := map[string]string{
+		"body": gitLabNoteBody(entry.Note, spent) + "\n\n/spend " + gitLabDuration(spent) + " " + entry.Period.Started.Format("2006-01-02"),
+	}
+	var note struct {
+		ID int `json:"id"`
+	}
+	if err := s.makeGitLabRequest(integration, http.MethodPost, path+"/notes", body, &note); err != nil {
+		return "", errors.New("failed to add time entry to GitLab: " + err.Error())
+	}
+	if note.ID == 0 {
+		return "", errors.New("note ID not found in GitLab response")
+	}
+	return fmt.Sprintf("%d:%d", note.ID, spent), nil
+}
+
+func (s *GitLabService) updateWorklog(integration *models.GitLabIntegration, entry *models.TimeEntry, issueID string, worklogID string) (string, error) {
+	path, err := gitLabIssuePath(issueID)
+	if err != nil {
+		return "", err
+	}
+	noteID, previous, err := parseGitLabWorklogID(worklogID)
+	if err != nil {
+		return "", err
+	}
+
+	// Update the note first, so a retry after a failed correction does not
+	// apply the difference twice.
+	spent := gitLabSpentSeconds(entry.Period.Duration)
+	body := map[string]string{"body": gitLabNoteBody(entry.Note, spent)}
+	if err := s.makeGitLabRequest(integration, http.MethodPut, path+"/notes/"+noteID, body, nil); err != nil {
+		return "", errors.New("failed to update time entry in GitLab: " + err.Error())
+	}
+	if spent != previous {
+		if err := s.addSpentTime(integration, path, spent-previous); err != nil {
+			return "", errors.New("failed to update time entry in GitLab: " + err.Error())
+		}
+	}
+	return fmt.Sprintf("%s:%d", noteID, spent), nil
+}
+
+func (s *GitLabService) deleteWorklog(integration *models.GitLabIntegration, issueID string, worklogID string) error {
+	path, err := gitLabIssuePath(issueID)
+	if err != nil {
+		return err
+	}
+	noteID, previous, err := parseGitLabWorklogID(worklogID)
+	if err != nil {
+		return err
+	}
+
+	// The note is removed before the time, so a retry does not subtract twice.
+	err = s.makeGitLabRequest(integration, http.MethodDelete, path+"/notes/"+noteID, nil, nil)
+	if err != nil && !errors.Is(err, errGitLabNotFound) {
+		return errors.New("failed to remove time entry from GitLab: " + err.Error())
+	}
+	if previous > 0 {
+		if err := s.addSpentTime(integration, path, -previous); err != nil {
+			return errors.New("failed to remove time entry from GitLab: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// addSpentTime posts a note that only holds a "/spend" quick action. GitLab
+// applies it without keeping the note.
+func (s *GitLabService) addSpentTime(integration *models.GitLabIntegration, issuePath string, seconds int) error {
+	body := map[string]string{"body": "/spend " + gitLabDuration(seconds)}
+	return s.makeGitLabRequest(integration, http.MethodPost, issuePath+"/notes", body, nil)
+}
+
+func (s *GitLabService) getIntegration(userID string) (*models.GitLabIntegration, error) {
+	integration, err := s.userService.GetGitLabIntegration(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !integration.Enabled || integration.AccessToken == "" {
+		return nil, ErrGitLabNotConnected
+	}
+	if integration.BaseURL == "" {
+		integration.BaseURL = strings.TrimRight(s.config.BaseURL, "/")
+	}
+	return integration, nil
+}
+
+func (s *GitLabService) makeGitLabRequest(integration *models.GitLabIntegration, method, path string, reqBody interface{}, respTarget interface{}) error {
+	var body io.Reader
+	if reqBody != nil {
+		jsonBody, err := json.Marshal(reqBody)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, integration.BaseURL+"/api/v4"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", integration.AccessToken)
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errGitLabNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr gitLabAPIErr
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message != nil {
+			return fmt.Errorf("GitLab API returned status: %s, message: %v", resp.Status, apiErr.Message)
+		}
+		if apiErr.Error != "" {
+			return fmt.Errorf("GitLab API returned status: %s, message: %s", resp.Status, apiErr.Error)
+		}
+		return fmt.Errorf("GitLab API returned status: %s", resp.Status)
+	}
+
+	if respTarget != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(respTarget); err != nil {
+			return fmt.Errorf("failed to decode GitLab response: %w", err)
+		}
+	}
+	return nil
+}
+
+// gitLabBaseURL checks the instance a user connects to, or returns the default
+// instance when raw is empty. The access token is sent there, so only https is
+// accepted. When allowed hosts are configured, only those and the default
+// instance can be used, otherwise any host outside the local network.
+func gitLabBaseURL(c config.GitLabConfig, raw string) (string, error) {
+	if raw == "" {
+		return strings.TrimRight(c.BaseURL, "/"), nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w: %s", ErrGitLabURLNotAllowed, raw)
+	}
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("%w: %s, only https is supported", ErrGitLabURLNotAllowed, raw)
+	}
+
+	host := u.Hostname()
+	baseURL := u.Scheme + "://" + u.Host + strings.TrimRight(u.EscapedPath(), "/")
+	if defaultURL, err := url.Parse(c.BaseURL); err == nil && strings.EqualFold(defaultURL.Hostname(), host) {
+		return baseURL, nil
+	}
+	for _, allowed := range c.AllowedHosts {
+		if strings.EqualFold(allowed, host) {
+			return baseURL, nil
+		}
+	}
+	if len(c.AllowedHosts) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrGitLabURLNotAllowed, raw)
+	}
+
+	host = strings.ToLower(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", fmt.Errorf("%w: %s", ErrGitLabURLNotAllowed, raw)
+	}
+	if ip := net.ParseIP(host); ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast()) {
+		return "", fmt.Errorf("%w: %s", ErrGitLabURLNotAllowed, raw)
+	}
+	return baseURL, nil
+}
+
+// gitLabIssuePath turns an issue reference like "group/project#12" into the
+// API path of the issue.
+func gitLabIssuePath(ref string) (string, error) {
+	i := strings.LastIndex(ref, "#")
+	if i <= 0 || i == len(ref)-1 {
+		return "", errors.New("invalid GitLab issue reference, expected group/project#123: " + ref)
+	}
+	iid := ref[i+1:]
+	if _, err := strconv.Atoi(iid); err != nil {
+		return "", errors.New("invalid GitLab issue reference, expected group/project#123: " + ref)
+	}
+	return "/projects/" + url.PathEscape(ref[:i]) + "/issues/" + iid, nil
+}
+
+func parseGitLabWorklogID(worklogID string) (string, int, error) {
+	noteID, spent, ok := strings.Cut(worklogID, ":")
+	seconds, err := strconv.Atoi(spent)
+	if !ok || noteID == "" || err != nil {
+		return "", 0, errors.New("invalid GitLab worklog ID: " + worklogID)
+	}
+	return noteID, seconds, nil
+}
+
+// gitLabSpentSeconds rounds a duration to whole minutes, the smallest unit
+// GitLab tracks, logging at least one minute.
+func gitLabSpentSeconds(seconds int) int {
+	minutes := (seconds + 30) / 60
+	if minutes < 1 {
+		minutes = 1
+	}
+	return minutes * 60
+}
+
+// gitLabDuration formats seconds for a "/spend" quick action, e.g. "90m" or "-15m".
+func gitLabDuration(seconds int) string {
+	return strconv.Itoa(seconds/60) + "m"
+}
+
+func gitLabNoteBody(note string, seconds int) string {
+	minutes := seconds / 60
+	body := fmt.Sprintf("Logged %dh %02dm with TimeTrack", minutes/60, minutes%60)
+	if note != "" {
+		body += ": " + note
+	}
+	return body
+}
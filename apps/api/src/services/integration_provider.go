@@ -0,0 +1,82 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	ErrUnknownIntegration = errors.New("unknown integration type")
+	ErrInvalidIntegration = errors.New("invalid integration")
+	ErrWorklogNotFound    = errors.New("worklog not found")
+)
+
+// IntegrationProvider is implemented by every external system that projects
+// can be linked to and time can be reported to.
+type IntegrationProvider interface {
+	// Type is the value stored in IntegrationInfo.Type and ReportStatus.Integration.
+	Type() string
+	// VerifyIssue returns an error when the issue does not exist or the user
+	// cannot access it.
+	VerifyIssue(userID string, issueKey string) error
+	// LinkProject returns the integration details for a project bound to the issue.
+	LinkProject(userID string, issueKey string) (models.IntegrationInfo, error)
+	// CreateWorklog logs the entry on the issue and returns the ID of the worklog.
+	CreateWorklog(entry *models.TimeEntry, issueID string) (string, error)
+	// UpdateWorklog changes an existing worklog and returns its ID, which may
+	// differ from worklogID.
+	UpdateWorklog(entry *models.TimeEntry, issueID string, worklogID string) (string, error)
+	DeleteWorklog(userID string, issueID string, worklogID string) error
+}
+
+// WorklogLister is implemented by providers whose worklogs can be read back,
+// which is needed to reconcile them with local entries.
+type WorklogLister interface {
+	// ListWorklogs returns the worklogs of the user on the issues that started
+	// within the range.
+	ListWorklogs(userID string, issueIDs []string, from, to time.Time) ([]models.Worklog, error)
+	// GetWorklog returns a single worklog, or ErrWorklogNotFound.
+	GetWorklog(userID string, issueID string, worklogID string) (*models.Worklog, error)
+}
+
+// WorklogFetcher is implemented by providers that can list every worklog of
+// the user regardless of the issue, which is needed to import them.
+type WorklogFetcher interface {
+	// FetchWorklogs returns the worklogs the user authored that started
+	// within the range, with IssueKey set.
+	FetchWorklogs(userID string, from, to time.Time) ([]models.Worklog, error)
+}
+
+// IntegrationRegistry looks up integration providers by type.
+type IntegrationRegistry struct {
+	providers map[string]IntegrationProvider
+}
+
+func NewIntegrationRegistry(providers ...IntegrationProvider) *IntegrationRegistry {
+	r := &IntegrationRegistry{providers: make(map[string]IntegrationProvider)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+func (r *IntegrationRegistry) Register(p IntegrationProvider) {
+	r.providers[p.Type()] = p
+}
+
+func (r *IntegrationRegistry) Get(integrationType string) (IntegrationProvider, bool) {
+	p, ok := r.providers[integrationType]
+	return p, ok
+}
+
+// Types returns the registered integration types in alphabetical order.
+func (r *IntegrationRegistry) Types() []string {
+	types := make([]string, 0, len(r.providers))
+	for t := range r.providers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"html/template"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthResultPage is shown in the browser at the end of an OAuth flow, which
+// the CLI starts by opening the browser.
+var oauthResultPage = template.Must(template.New("oauth").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>TimeTrack - {{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; background: #f4f5f7; color: #172b4d; display: flex; align-items: center; justify-content: center; min-height: 100vh; margin: 0; }
+main { background: #fff; border-radius: 8px; box-shadow: 0 1px 3px rgba(0,0,0,.15); padding: 2rem 3rem; max-width: 28rem; text-align: center; }
+h1 { font-size: 1.4rem; color: {{if .Success}}#216e4e{{else}}#ae2e24{{end}}; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+<p>You can now close this window and return to TimeTrack.</p>
+</main>
+</body>
+</html>
+`))
+
+func renderOAuthResult(c *gin.Context, status int, success bool, title string, message string) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	err := oauthResultPage.Execute(c.Writer, gin.H{
+		"Success": success,
+		"Title":   title,
+		"Message": message,
+	})
+	if err != nil {
+		log.Printf("Error rendering OAuth result page: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// oauthStateLifetime is how long the user has to finish the OAuth flow.
+const oauthStateLifetime = 10 * time.Minute
+
+var ErrInvalidOAuthState = errors.New("invalid or expired OAuth state")
+
+// oauthState is the server side record of an OAuth flow started by a user.
+type oauthState struct {
+	ID        string    `bson:"_id"` // random nonce, the first part of the state token
+	UserID    string    `bson:"user_id"`
+	Provider  string    `bson:"provider"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// OAuthStateService issues the state parameter of OAuth flows. A state is a
+// random nonce stored with the user that started the flow, plus an HMAC of the
+// nonce, so a callback can only complete a flow that this server started. Each
+// state can be used once.
+type OAuthStateService struct {
+	stateCollection *mongo.Collection
+	secret          []byte
+}
+
+func NewOAuthStateService(db *mongo.Database, secret string) *OAuthStateService {
+	return &OAuthStateService{
+		stateCollection: db.Collection("oauth_states"),
+		secret:          []byte(secret),
+	}
+}
+
+// Create starts a flow for the user with the provider and returns the state
+// token to send along.
+func (s *OAuthStateService) Create(ctx context.Context, userID string, provider string) (string, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	state := oauthState{
+		ID:        base64.RawURLEncoding.EncodeToString(nonce),
+		UserID:    userID,
+		Provider:  provider,
+		ExpiresAt: now.Add(oauthStateLifetime),
+		CreatedAt: now,
+	}
+	// Flows that were never finished are cleaned up here.
+	_, _ = s.stateCollection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": now}})
+	if _, err := s.stateCollection.InsertOne(ctx, state); err != nil {
+		return "", err
+	}
+	return state.ID + "." + s.sign(state.ID, provider), nil
+}
+
+// Consume verifies the state token of a callback and returns the user that
+// started the flow. The state is deleted, so a second callback with it fails.
+func (s *OAuthStateService) Consume(ctx context.Context, token string, provider string) (string, error) {
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" || !hmac.Equal([]byte(signature), []byte(s.sign(nonce, provider))) {
+		return "", ErrInvalidOAuthState
+	}
+
+	filter := bson.M{"_id": nonce, "provider": provider, "expires_at": bson.M{"$gt": time.Now()}}
+	var state oauthState
+	if err := s.stateCollection.FindOneAndDelete(ctx, filter).Decode(&state); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", ErrInvalidOAuthState
+		}
+		return "", err
+	}
+	return state.UserID, nil
+}
+
+func (s *OAuthStateService) sign(nonce string, provider string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(provider + ":" + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
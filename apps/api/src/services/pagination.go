@@ -0,0 +1,47 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor is the position after the last item of a page: the value of the
+// field the list is sorted by and the ID, which orders items with equal values.
+type pageCursor struct {
+	Key string `json:"k"`
+	ID  string `json:"id"`
+}
+
+// encodeCursor returns the opaque cursor that clients pass back to fetch the
+// next page.
+func encodeCursor(key, id string) string {
+	data, _ := json.Marshal(pageCursor{Key: key, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (pageCursor, error) {
+	var c pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.ID == "" {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// afterCursor adds to filter the condition for the items that come after the
+// cursor when sorting by field and then _id, both descending or both ascending.
+func afterCursor(filter bson.M, field string, value interface{}, id string, descending bool) {
+	op := "$gt"
+	if descending {
+		op = "$lt"
+	}
+	filter["$and"] = bson.A{bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, "_id": bson.M{op: id}},
+	}}}
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 in points, the unit of PDF coordinates. The origin is the bottom left
+// corner of the page.
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+)
+
+// helveticaWidths are the widths of the printable ASCII characters, from the
+// space up to the tilde, in the standard Helvetica font at size 1000.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// winAnsiExtras maps the characters outside of Latin-1 that WinAnsiEncoding
+// places between 0x80 and 0x9f.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// pdfWriter builds a document of text and lines in the standard Helvetica
+// fonts, which every PDF reader has, so no fonts are embedded. Pages are kept
+// in memory until the document is written.
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+func newPDFWriter() *pdfWriter {
+	return &pdfWriter{}
+}
+
+func (p *pdfWriter) AddPage() {
+	p.page = &bytes.Buffer{}
+	p.pages = append(p.pages, p.page)
+}
+
+func (p *pdfWriter) PageCount() int {
+	return len(p.pages)
+}
+
+// Text draws s with its baseline starting at x, y on the current page.
+func (p *pdfWriter) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(s))
+}
+
+// TextRight draws s so that it ends at x.
+func (p *pdfWriter) TextRight(x, y, size float64, bold bool, s string) {
+	p.Text(x-pdfTextWidth(s, size), y, size, bold, s)
+}
+
+func (p *pdfWriter) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(p.page, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// WriteTo writes the document. Each page has its number and the page count at
+// the bottom.
+func (p *pdfWriter) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	var offsets []int64
+	object := func(format string, args ...interface{}) {
+		offsets = append(offsets, cw.n)
+		fmt.Fprintf(cw, "%d 0 obj\n", len(offsets))
+		fmt.Fprintf(cw, format, args...)
+		_, _ = io.WriteString(cw, "\nendobj\n")
+	}
+
+	_, _ = io.WriteString(cw, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1 to 4 are shared, then every page is followed by its contents.
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range p.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(p.pages))
+		fmt.Fprintf(page, "BT /F1 8.0 Tf %.2f 30.00 Td (%s) Tj ET\n", pdfPageWidth-50-pdfTextWidth(footer, 8), footer)
+		object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i)
+		object("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes())
+	}
+
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(cw, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, bw.Flush()
+}
+
+// pdfTextWidth returns the width of s in Helvetica at the size. Bold text is
+// slightly wider, but its digits have the same width.
+func pdfTextWidth(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		if r >= ' ' && r <= '~' {
+			total += helveticaWidths[r-' ']
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfTruncate shortens s with an ellipsis to fit in width.
+func pdfTruncate(s string, size, width float64) string {
+	if pdfTextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdfTextWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+// pdfEscape encodes s in WinAnsiEncoding as the body of a PDF string.
+// Characters that cannot be encoded are replaced with a question mark.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		var c byte
+		switch extra, ok := winAnsiExtras[r]; {
+		case ok:
+			c = extra
+		case r == '\t' || r == '\n' || r == '\r':
+			c = ' '
+		case r >= ' ' && r <= '~', r >= 0xa0 && r <= 0xff:
+			c = byte(r)
+		default:
+			c = '?'
+		}
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Permission is something a user can be allowed to do in a workspace.
+type Permission string
+
+const (
+	PermLogTime          Permission = "log_time"          // log time against the projects
+	PermCreateProject    Permission = "create_project"    // add projects
+	PermViewTeam         Permission = "view_team"         // see the time of other members
+	PermReviewTimesheets Permission = "review_timesheets" // approve and reject timesheets of other members
+	PermManageProjects   Permission = "manage_projects"   // rename, relink and delete projects
+	PermManageMembers    Permission = "manage_members"    // add and remove managers and members
+	PermManageBilling    Permission = "manage_billing"    // manage clients and hourly rates
+	PermViewAudit        Permission = "view_audit"        // read the audit log
+	PermManageAdmins     Permission = "manage_admins"     // add, remove and change admins
+)
+
+var rolePermissions = map[string][]Permission{
+	models.RoleMember:  {PermLogTime, PermCreateProject},
+	models.RoleManager: {PermLogTime, PermCreateProject, PermViewTeam, PermReviewTimesheets},
+	models.RoleAdmin:   {PermLogTime, PermCreateProject, PermViewTeam, PermReviewTimesheets, PermManageProjects, PermManageMembers, PermManageBilling, PermViewAudit},
+	models.RoleOwner:   {PermLogTime, PermCreateProject, PermViewTeam, PermReviewTimesheets, PermManageProjects, PermManageMembers, PermManageBilling, PermViewAudit, PermManageAdmins},
+}
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionService decides what users may do in workspaces. Outside of a
+// workspace users work with their own data and may do everything.
+type PermissionService struct {
+	workspaceService *WorkspaceService
+	auditService     *AuditService
+}
+
+func NewPermissionService(ws *WorkspaceService, as *AuditService) *PermissionService {
+	return &PermissionService{workspaceService: ws, auditService: as}
+}
+
+// RoleOf returns the role of the user in the workspace, or an empty string when
+// the user is not a member. Members added before roles existed are members,
+// except for the owner.
+func RoleOf(workspace *models.Workspace, userID string) string {
+	for _, member := range workspace.Members {
+		if member.UserID != userID {
+			continue
+		}
+		switch {
+		case member.Role != "":
+			return member.Role
+		case userID == workspace.OwnerID:
+			return models.RoleOwner
+		default:
+			return models.RoleMember
+		}
+	}
+	return ""
+}
+
+// HasPermission reports whether the role grants the permission.
+func HasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns nil when the user has the permission in the workspace, and
+// otherwise records the denial for the resource and returns ErrPermissionDenied.
+func (s *PermissionService) Authorize(ctx context.Context, userID string, workspaceID string, permission Permission, resource string) error {
+	if workspaceID == "" {
+		return nil
+	}
+
+	role := ""
+	workspace, err := s.workspaceService.GetWorkspace(ctx, workspaceID, userID)
+	switch {
+	case err == nil:
+		role = RoleOf(workspace, userID)
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return err
+	}
+	if HasPermission(role, permission) {
+		return nil
+	}
+
+	event := &models.AuditEvent{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Role:        role,
+		Permission:  string(permission),
+		Resource:    resource,
+	}
+	if err := s.auditService.Record(ctx, event); err != nil {
+		log.Printf("Error recording denied %s for user %s: %v", permission, userID, err)
+	}
+	return fmt.Errorf("%w: %s requires %s", ErrPermissionDenied, resource, permission)
+}
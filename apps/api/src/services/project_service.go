@@ -3,6 +3,9 @@ package services
 import (
 	"TimeTrack-shared/models"
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,33 +14,73 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// issueKeyPattern matches Jira issue keys such as "ABC-123". Keys are upper
+// case, so words like "covid-19" in a project name are not taken for one.
+var issueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
 type ProjectService struct {
 	projectCollection *mongo.Collection
-	atlassianService  *AtlassianService
+	integrations      *IntegrationRegistry
+	settingsService   *SettingsService
+	workspaceService  *WorkspaceService
 }
 
-func NewProjectService(db *mongo.Database, as *AtlassianService) *ProjectService {
+func NewProjectService(db *mongo.Database, ir *IntegrationRegistry, ss *SettingsService, ws *WorkspaceService) *ProjectService {
 	return &ProjectService{
 		projectCollection: db.Collection("projects"),
-		atlassianService:  as,
+		integrations:      ir,
+		settingsService:   ss,
+		workspaceService:  ws,
+	}
+}
+
+// scopeFilter matches the projects of a workspace, or the personal projects of
+// the owner when workspaceID is empty.
+func scopeFilter(ownerID string, workspaceID string) bson.M {
+	if workspaceID != "" {
+		return bson.M{"workspace_id": workspaceID, "deleted_at": bson.M{"$eq": nil}}
 	}
+	return bson.M{"owner_id": ownerID, "workspace_id": bson.M{"$in": bson.A{nil, ""}}, "deleted_at": bson.M{"$eq": nil}}
+}
+
+// accessFilter matches every project the user can log time against: their
+// personal projects and the projects of their workspaces.
+func (s *ProjectService) accessFilter(ctx context.Context, userID string) (bson.M, error) {
+	workspaceIDs, err := s.workspaceService.GetWorkspaceIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{
+		"deleted_at": bson.M{"$eq": nil},
+		"$or": bson.A{
+			bson.M{"owner_id": userID, "workspace_id": bson.M{"$in": bson.A{nil, ""}}},
+			bson.M{"workspace_id": bson.M{"$in": workspaceIDs}},
+		},
+	}, nil
 }
 
 func (s *ProjectService) CreateProject(ctx context.Context, project *models.Project) error {
-	// This should be moved to settings later
-	AUTO_LINK_TO_JIRA := true
-
-	if AUTO_LINK_TO_JIRA {
-		err := s.atlassianService.CheckIfJiraTicketExists(project.OwnerID, project.Name)
-		if err == nil {
-			project.Integration = models.IntegrationInfo{
-				Type:       "jira",
-				Key:        project.Name,
-				ExternalID: project.Name,
-			}
+	if project.Integration.Type == "" {
+		if err := s.autoLinkProject(ctx, project); err != nil {
+			return err
+		}
+	} else {
+		integration, err := s.LinkIntegration(project.OwnerID, project.Integration)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidIntegration, err)
 		}
+		project.Integration = integration
 	}
+	return s.insertProject(ctx, project)
+}
+
+// CreateLinkedProject creates a project with integration details that came
+// from the provider itself, so they are stored without verifying them again.
+func (s *ProjectService) CreateLinkedProject(ctx context.Context, project *models.Project) error {
+	return s.insertProject(ctx, project)
+}
 
+func (s *ProjectService) insertProject(ctx context.Context, project *models.Project) error {
 	project.ID = uuid.New().String()
 	project.CreatedAt = time.Now()
 	project.UpdatedAt = time.Now()
@@ -46,6 +89,62 @@ func (s *ProjectService) CreateProject(ctx context.Context, project *models.Proj
 	return err
 }
 
+// autoLinkProject links the project to the Jira issue in its name when the
+// owner has auto-linking enabled and the issue exists.
+func (s *ProjectService) autoLinkProject(ctx context.Context, project *models.Project) error {
+	settings, err := s.settingsService.GetSettings(ctx, project.OwnerID)
+	if err != nil {
+		return err
+	}
+	jira, ok := s.integrations.Get("jira")
+	if !settings.AutoLinkJira || !ok {
+		return nil
+	}
+
+	candidates := []string{project.Name}
+	if settings.FuzzyAutoLink {
+		candidates = issueKeyCandidates(project.Name)
+	}
+	for _, key := range candidates {
+		if integration, err := jira.LinkProject(project.OwnerID, key); err == nil {
+			project.Integration = integration
+			return nil
+		}
+	}
+	return nil
+}
+
+// LinkIntegration verifies the issue with the provider of the integration type
+// and returns the integration details to store on the project.
+func (s *ProjectService) LinkIntegration(ownerID string, info models.IntegrationInfo) (models.IntegrationInfo, error) {
+	provider, ok := s.integrations.Get(info.Type)
+	if !ok {
+		return models.IntegrationInfo{}, fmt.Errorf("%w: %s", ErrUnknownIntegration, info.Type)
+	}
+	key := info.Key
+	if key == "" {
+		key = info.ExternalID
+	}
+	if key == "" {
+		return models.IntegrationInfo{}, errors.New("integration key is required")
+	}
+	return provider.LinkProject(ownerID, key)
+}
+
+// issueKeyCandidates returns the distinct issue keys found in name, exactly as
+// written, in the order they appear.
+func issueKeyCandidates(name string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range issueKeyPattern.FindAllString(name, -1) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 func (s *ProjectService) UpdateProject(ctx context.Context, id string, update bson.M) error {
 	update["updated_at"] = time.Now()
 	_, err := s.projectCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
@@ -58,31 +157,166 @@ func (s *ProjectService) DeleteProject(ctx context.Context, id string) error {
 	return err
 }
 
-func (s *ProjectService) GetProjects(ctx context.Context, ownerID string, nameFilter string, ids []string, skip, limit int64) ([]models.Project, error) {
-	filter := bson.M{"owner_id": ownerID, "deleted_at": bson.M{"$eq": nil}}
+// GetProjects returns a page of the projects of the workspace, or the personal
+// projects of the owner when workspaceID is empty, sorted by name, with the
+// total number of matching projects. Projects looked up by ID are returned
+// from any workspace of the owner, since time entries can belong to any of them.
+// The page starts after the cursor of the previous page if one is given.
+func (s *ProjectService) GetProjects(ctx context.Context, ownerID string, workspaceID string, nameFilter string, ids []string, cursor string, skip, limit int64) (*models.ProjectPage, error) {
+	filter := scopeFilter(ownerID, workspaceID)
+	if ids != nil {
+		var err error
+		if filter, err = s.accessFilter(ctx, ownerID); err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
 	if nameFilter != "" {
 		filter["name"] = bson.M{"$regex": nameFilter, "$options": "i"}
 	}
 
-	if ids != nil {
-		filter["_id"] = bson.M{"$in": ids}
+	total, err := s.projectCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		afterCursor(filter, "name", after.Key, after.ID, false)
+	}
+
+	// One more than the limit is fetched to tell whether there is a next page.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit + 1)
+	found, err := s.projectCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	opts := options.Find().SetSkip(skip).SetLimit(limit)
+	page := &models.ProjectPage{Projects: []models.Project{}, Total: total}
+	if err := found.All(ctx, &page.Projects); err != nil {
+		return nil, err
+	}
+	if int64(len(page.Projects)) > limit {
+		page.Projects = page.Projects[:limit]
+		last := page.Projects[limit-1]
+		page.NextCursor = encodeCursor(last.Name, last.ID)
+	}
+	return page, nil
+}
+
+// GetWorkspaceProjectIDs returns the IDs of all projects of the workspace,
+// including deleted ones, since time may have been logged against them.
+func (s *ProjectService) GetWorkspaceProjectIDs(ctx context.Context, workspaceID string) ([]string, error) {
+	return s.findProjectIDs(ctx, bson.M{"workspace_id": workspaceID})
+}
+
+// GetClientProjectIDs returns the IDs of all projects billed to the client,
+// including deleted ones.
+func (s *ProjectService) GetClientProjectIDs(ctx context.Context, clientID string) ([]string, error) {
+	return s.findProjectIDs(ctx, bson.M{"client_id": clientID})
+}
+
+func (s *ProjectService) findProjectIDs(ctx context.Context, filter bson.M) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
 	cursor, err := s.projectCollection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
 
-	var projects []models.Project
+// GetIntegrationProjectIDs returns the IDs of the projects the user can access
+// that are linked to the integration.
+func (s *ProjectService) GetIntegrationProjectIDs(ctx context.Context, userID string, integration string) ([]string, error) {
+	filter, err := s.accessFilter(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter["integration.type"] = integration
+	return s.findProjectIDs(ctx, filter)
+}
+
+// GetProjectsByIDs returns the projects with the IDs, including deleted ones,
+// regardless of who can access them.
+func (s *ProjectService) GetProjectsByIDs(ctx context.Context, ids []string) ([]models.Project, error) {
+	cursor, err := s.projectCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	projects := []models.Project{}
 	if err := cursor.All(ctx, &projects); err != nil {
 		return nil, err
 	}
 	return projects, nil
 }
 
-func (s *ProjectService) GetProjectByID(ctx context.Context, id string, ownerId string) (*models.Project, error) {
-	filter := bson.M{"_id": id, "deleted_at": bson.M{"$eq": nil}, "owner_id": ownerId}
+// HasClientProjects reports whether any project that is not deleted bills to
+// the client.
+func (s *ProjectService) HasClientProjects(ctx context.Context, clientID string) (bool, error) {
+	count, err := s.projectCollection.CountDocuments(ctx, bson.M{"client_id": clientID, "deleted_at": bson.M{"$eq": nil}}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// GetLinkedProjects returns the projects the user can access that are bound to
+// an issue in the integration.
+func (s *ProjectService) GetLinkedProjects(ctx context.Context, ownerID string, integration string) ([]models.Project, error) {
+	filter, err := s.accessFilter(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	filter["integration.type"] = integration
+	filter["integration.external_id"] = bson.M{"$ne": ""}
+	cursor, err := s.projectCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	projects := []models.Project{}
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetProjectByIssue returns the project in the workspace, or the personal
+// project when workspaceID is empty, bound to the issue, or mongo.ErrNoDocuments.
+// Projects linked before issue IDs were stored hold the key as external ID, so
+// both are matched.
+func (s *ProjectService) GetProjectByIssue(ctx context.Context, ownerID string, workspaceID string, integration string, issueID string, issueKey string) (*models.Project, error) {
+	filter := scopeFilter(ownerID, workspaceID)
+	filter["integration.type"] = integration
+	filter["$or"] = bson.A{
+		bson.M{"integration.external_id": bson.M{"$in": bson.A{issueID, issueKey}}},
+		bson.M{"integration.key": issueKey},
+	}
+	var project models.Project
+	if err := s.projectCollection.FindOne(ctx, filter).Decode(&project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetProjectByName returns the project in the workspace, or the personal
+// project when workspaceID is empty, whose name matches exactly, ignoring case,
+// or mongo.ErrNoDocuments.
+func (s *ProjectService) GetProjectByName(ctx context.Context, ownerID string, workspaceID string, name string) (*models.Project, error) {
+	filter := scopeFilter(ownerID, workspaceID)
+	filter["name"] = bson.M{"$regex": "^" + regexp.QuoteMeta(name) + "$", "$options": "i"}
 	var project models.Project
 	err := s.projectCollection.FindOne(ctx, filter).Decode(&project)
 	if err != nil {
@@ -90,3 +324,19 @@ func (s *ProjectService) GetProjectByID(ctx context.Context, id string, ownerId
 	}
 	return &project, nil
 }
+
+// GetProjectByID returns the project if the user can access it, either as a
+// personal project or through a workspace, or mongo.ErrNoDocuments.
+func (s *ProjectService) GetProjectByID(ctx context.Context, id string, userID string) (*models.Project, error) {
+	filter, err := s.accessFilter(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter["_id"] = id
+	var project models.Project
+	err = s.projectCollection.FindOne(ctx, filter).Decode(&project)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
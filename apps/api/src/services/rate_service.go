@@ -0,0 +1,121 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Specificity of the rate targets. A rate for a user on a project beats one for
+// the project, which beats one for the user on the client, and so on.
+const (
+	rateUserWeight    = 1
+	rateClientWeight  = 2
+	rateProjectWeight = 4
+)
+
+type RateService struct {
+	rateCollection *mongo.Collection
+}
+
+func NewRateService(db *mongo.Database) *RateService {
+	return &RateService{rateCollection: db.Collection("rates")}
+}
+
+func (s *RateService) CreateRate(ctx context.Context, rate *models.Rate) error {
+	rate.ID = uuid.New().String()
+	rate.CreatedAt = time.Now()
+	if rate.EffectiveFrom.IsZero() {
+		rate.EffectiveFrom = rate.CreatedAt
+	}
+	_, err := s.rateCollection.InsertOne(ctx, rate)
+	return err
+}
+
+// GetRates returns the rates of the workspace, or the personal rates of the
+// owner when workspaceID is empty, the latest effective first. Non-empty
+// targets only return the history of rates for exactly that target.
+func (s *RateService) GetRates(ctx context.Context, ownerID string, workspaceID string, target *models.Rate) ([]models.Rate, error) {
+	filter := scopeFilter(ownerID, workspaceID)
+	if target != nil {
+		filter["client_id"] = optionalField(target.ClientID)
+		filter["project_id"] = optionalField(target.ProjectID)
+		filter["user_id"] = optionalField(target.UserID)
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "effective_from", Value: -1}, {Key: "created_at", Value: -1}})
+	cursor, err := s.rateCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	rates := []models.Rate{}
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// DeleteRate deletes a rate of the workspace, or a personal rate of the owner
+// when workspaceID is empty. It returns mongo.ErrNoDocuments when there is
+// none.
+func (s *RateService) DeleteRate(ctx context.Context, id string, ownerID string, workspaceID string) error {
+	filter := scopeFilter(ownerID, workspaceID)
+	filter["_id"] = id
+	res, err := s.rateCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// optionalField matches the value, or a missing field when value is empty.
+func optionalField(value string) interface{} {
+	if value == "" {
+		return bson.M{"$in": bson.A{nil, ""}}
+	}
+	return value
+}
+
+// rateTable holds the rates of a workspace, or the personal rates of a user,
+// the latest effective first.
+type rateTable []models.Rate
+
+// rateFor returns the rate of the most specific target matching the entry that
+// was effective when the entry started, or nil when there is none.
+func (t rateTable) rateFor(entry *models.TimeEntry, clientID string) *models.Rate {
+	var best *models.Rate
+	bestWeight := 0
+	for i := range t {
+		rate := &t[i]
+		if rate.EffectiveFrom.After(entry.Period.Started) {
+			continue
+		}
+		if (rate.ProjectID != "" && rate.ProjectID != entry.ProjectID) ||
+			(rate.ClientID != "" && rate.ClientID != clientID) ||
+			(rate.UserID != "" && rate.UserID != entry.OwnerID) {
+			continue
+		}
+		weight := 0
+		if rate.ProjectID != "" {
+			weight += rateProjectWeight
+		}
+		if rate.ClientID != "" {
+			weight += rateClientWeight
+		}
+		if rate.UserID != "" {
+			weight += rateUserWeight
+		}
+		// The first rate of a weight is its latest effective one.
+		if weight > bestWeight {
+			best, bestWeight = rate, weight
+		}
+	}
+	return best
+}
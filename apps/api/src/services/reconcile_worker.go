@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ReconcileWorker periodically reconciles recent Jira worklogs of every user
+// with a working Atlassian integration, so drift shows up on the entries
+// without anyone asking for a report.
+type ReconcileWorker struct {
+	userService      *UserService
+	timeEntryService *TimeEntryService
+	interval         time.Duration
+	window           time.Duration
+}
+
+func NewReconcileWorker(us *UserService, tes *TimeEntryService, interval time.Duration, window time.Duration) *ReconcileWorker {
+	return &ReconcileWorker{
+		userService:      us,
+		timeEntryService: tes,
+		interval:         interval,
+		window:           window,
+	}
+}
+
+// Run reconciles the last window of time every interval until the context is
+// cancelled.
+func (w *ReconcileWorker) Run(ctx context.Context) {
+	log.Printf("Reconcile worker started, checking the last %s every %s", w.window, w.interval)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Reconcile worker stopped")
+			return
+		case <-ticker.C:
+			w.reconcileUsers(ctx)
+		}
+	}
+}
+
+func (w *ReconcileWorker) reconcileUsers(ctx context.Context) {
+	userIDs, err := w.userService.GetAtlassianUserIDs(ctx)
+	if err != nil {
+		log.Printf("Error listing users for reconciliation: %v", err)
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-w.window)
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		report, err := w.timeEntryService.ReconcileWorklogs(ctx, userID, "jira", from, to)
+		if err != nil {
+			log.Printf("Error reconciling Jira worklogs of user %s: %v", userID, err)
+			continue
+		}
+		if len(report.Drift) > 0 {
+			log.Printf("Found %d differences between Jira worklogs and time entries of user %s", len(report.Drift), userID)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RenderMonthlyReport writes the report as a PDF or an HTML page.
+func RenderMonthlyReport(w io.Writer, report *MonthlyReport, format string) error {
+	switch format {
+	case "pdf":
+		_, err := renderReportPDF(report).WriteTo(w)
+		return err
+	case "html":
+		return reportTemplate.Execute(w, report)
+	default:
+		return ErrInvalidReportFormat
+	}
+}
+
+// formatReportDuration formats seconds as hours and minutes, e.g. "7:30".
+func formatReportDuration(seconds int64) string {
+	return fmt.Sprintf("%d:%02d", seconds/3600, seconds%3600/60)
+}
+
+// formatAmount formats an amount in the minor unit of the currency with two
+// decimals and thousands separators, e.g. "1,187.50 EUR".
+func formatAmount(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	units := strconv.FormatInt(amount/100, 10)
+	for i := len(units) - 3; i > 0; i -= 3 {
+		units = units[:i] + "," + units[i:]
+	}
+	return fmt.Sprintf("%s%s.%02d %s", sign, units, amount%100, currency)
+}
+
+// reportTitle returns the heading of the report, e.g. "Timesheet September 2026".
+func reportTitle(report *MonthlyReport) string {
+	return "Timesheet " + report.Start.Format("January 2006")
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": formatReportDuration,
+	"amount":   formatAmount,
+	"title":    reportTitle,
+	"billable": billableLine,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{title .}}{{with .Client}} - {{.Name}}{{end}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; color: #222; margin: 40px; }
+h1 { font-size: 24px; margin-bottom: 4px; }
+h2 { font-size: 17px; margin: 28px 0 6px; display: flex; justify-content: space-between; }
+table { width: 100%; border-collapse: collapse; }
+th, td { text-align: left; padding: 3px 6px; vertical-align: top; }
+th { border-bottom: 1px solid #999; }
+td.num, th.num { text-align: right; white-space: nowrap; }
+tr.day td { font-weight: bold; padding-top: 10px; border-bottom: 1px solid #ddd; }
+.meta { color: #666; margin: 2px 0; }
+.summary td { padding: 2px 12px 2px 0; }
+.billable { color: #666; margin-top: 6px; }
+.signoff { display: flex; gap: 60px; margin-top: 60px; }
+.signoff div { flex: 1; border-top: 1px solid #222; padding-top: 4px; }
+</style>
+</head>
+<body>
+<h1>{{title .}}</h1>
+{{with .Client}}<p class="meta">Client: {{.Name}}</p>{{end}}
+<p class="meta">Period: {{.Start.Format "2006-01-02"}} to {{(.End.AddDate 0 0 -1).Format "2006-01-02"}} ({{.Timezone}})</p>
+<p class="meta">Generated {{.GeneratedAt.Format "2006-01-02 15:04"}}</p>
+
+<table class="summary">
+<tr><td>Total time</td><td class="num">{{duration .TotalTime}}</td><td>{{.TotalEntries}} entries</td></tr>
+<tr><td>Billable time</td><td class="num">{{duration .BillableTime}}</td><td></td></tr>
+{{range .BillableTotals}}<tr><td>Billable amount</td><td class="num">{{amount .Amount .Currency}}</td><td>for {{duration .BillableTime}}</td></tr>
+{{end}}</table>
+{{$team := .Team}}
+{{range .Projects}}
+<h2><span>{{.Name}}{{with .IssueKey}} ({{.}}){{end}}</span><span>{{duration .TotalTime}}</span></h2>
+<table>
+<tr><th>Time</th><th class="num">Duration</th>{{if $team}}<th>User</th>{{end}}<th>Note</th></tr>
+{{range .Days}}<tr class="day"><td colspan="{{if $team}}3{{else}}2{{end}}">{{.Date.Format "Mon 2006-01-02"}}</td><td class="num">{{duration .TotalTime}}</td></tr>
+{{range .Entries}}<tr><td>{{.Start.Format "15:04"}}–{{.End.Format "15:04"}}</td><td class="num">{{duration .Duration}}</td>{{if $team}}<td>{{.User}}</td>{{end}}<td>{{.Note}}{{if not .Billable}} <em>(not billable)</em>{{end}}</td></tr>
+{{end}}{{end}}</table>
+{{range .Billable}}<p class="billable">{{billable .}}</p>
+{{end}}{{else}}
+<p>No time was logged in this month.</p>
+{{end}}
+<div class="signoff"><div>Approved by</div><div>Date</div></div>
+</body>
+</html>
+`))
+
+// Layout of the PDF report in points.
+const (
+	reportMargin     = 50.0
+	reportRight      = pdfPageWidth - reportMargin
+	reportBottom     = 60.0
+	reportLineHeight = 13.0
+)
+
+type reportPDF struct {
+	pdf *pdfWriter
+	y   float64
+}
+
+// space starts a new page unless height fits above the bottom margin.
+func (r *reportPDF) space(height float64) {
+	if r.pdf.PageCount() > 0 && r.y-height >= reportBottom {
+		return
+	}
+	r.pdf.AddPage()
+	r.y = pdfPageHeight - reportMargin
+}
+
+func renderReportPDF(report *MonthlyReport) *pdfWriter {
+	r := &reportPDF{pdf: newPDFWriter()}
+	r.space(0)
+
+	r.y -= 18
+	r.pdf.Text(reportMargin, r.y, 18, true, reportTitle(report))
+	r.y -= 8
+	meta := []string{}
+	if report.Client != nil {
+		meta = append(meta, "Client: "+report.Client.Name)
+	}
+	meta = append(meta,
+		fmt.Sprintf("Period: %s to %s (%s)", report.Start.Format("2006-01-02"), report.End.AddDate(0, 0, -1).Format("2006-01-02"), report.Timezone),
+		"Generated "+report.GeneratedAt.Format("2006-01-02 15:04"))
+	for _, line := range meta {
+		r.y -= reportLineHeight
+		r.pdf.Text(reportMargin, r.y, 10, false, line)
+	}
+
+	r.y -= 10
+	summary := [][2]string{
+		{"Total time", fmt.Sprintf("%s in %d entries", formatReportDuration(report.TotalTime), report.TotalEntries)},
+		{"Billable time", formatReportDuration(report.BillableTime)},
+	}
+	for _, total := range report.BillableTotals {
+		summary = append(summary, [2]string{"Billable amount", formatAmount(total.Amount, total.Currency)})
+	}
+	for _, row := range summary {
+		r.y -= reportLineHeight
+		r.pdf.Text(reportMargin, r.y, 10, true, row[0])
+		r.pdf.Text(reportMargin+110, r.y, 10, false, row[1])
+	}
+
+	if len(report.Projects) == 0 {
+		r.y -= 2 * reportLineHeight
+		r.pdf.Text(reportMargin, r.y, 10, false, "No time was logged in this month.")
+	}
+	for i := range report.Projects {
+		r.renderProject(&report.Projects[i], report.Team)
+	}
+
+	r.space(70)
+	r.y -= 60
+	half := (reportRight - reportMargin) / 2
+	r.pdf.Line(reportMargin, r.y, reportMargin+half-20, r.y, 0.5)
+	r.pdf.Line(reportMargin+half+20, r.y, reportRight, r.y, 0.5)
+	r.pdf.Text(reportMargin, r.y-11, 9, false, "Approved by")
+	r.pdf.Text(reportMargin+half+20, r.y-11, 9, false, "Date")
+	return r.pdf
+}
+
+func (r *reportPDF) renderProject(project *ReportProject, team bool) {
+	// Keep the heading with the first day and entry.
+	r.space(30 + 3*reportLineHeight)
+	r.y -= 30
+	name := project.Name
+	if project.IssueKey != "" {
+		name += " (" + project.IssueKey + ")"
+	}
+	total := formatReportDuration(project.TotalTime)
+	r.pdf.Text(reportMargin, r.y, 13, true, pdfTruncate(name, 13, reportRight-reportMargin-80))
+	r.pdf.TextRight(reportRight, r.y, 13, true, total)
+	r.y -= 5
+	r.pdf.Line(reportMargin, r.y, reportRight, r.y, 0.8)
+
+	noteX := reportMargin + 120
+	if team {
+		noteX += 130
+	}
+	for _, day := range project.Days {
+		r.space(2 * reportLineHeight)
+		r.y -= reportLineHeight + 4
+		r.pdf.Text(reportMargin, r.y, 10, true, day.Date.Format("Mon 2006-01-02"))
+		r.pdf.TextRight(reportRight, r.y, 10, true, formatReportDuration(day.TotalTime))
+		for _, entry := range day.Entries {
+			r.space(reportLineHeight)
+			r.y -= reportLineHeight
+			r.pdf.Text(reportMargin+10, r.y, 9, false, entry.Start.Format("15:04")+"–"+entry.End.Format("15:04"))
+			r.pdf.TextRight(reportMargin+110, r.y, 9, false, formatReportDuration(entry.Duration))
+			if team {
+				r.pdf.Text(reportMargin+120, r.y, 9, false, pdfTruncate(entry.User, 9, 125))
+			}
+			note := entry.Note
+			if !entry.Billable {
+				note = strings.TrimSpace(note + " (not billable)")
+			}
+			r.pdf.Text(noteX, r.y, 9, false, pdfTruncate(note, 9, reportRight-noteX))
+		}
+	}
+
+	for _, billable := range project.Billable {
+		r.space(reportLineHeight)
+		r.y -= reportLineHeight + 2
+		r.pdf.TextRight(reportRight, r.y, 9, false, billableLine(billable))
+	}
+}
+
+func billableLine(billable models.TimeEntryBillable) string {
+	if billable.Currency == "" {
+		return fmt.Sprintf("Billable %s, no rate", formatReportDuration(billable.BillableTime))
+	}
+	return fmt.Sprintf("Billable %s: %s", formatReportDuration(billable.BillableTime), formatAmount(billable.Amount, billable.Currency))
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SettingsService struct {
+	settingsCollection *mongo.Collection
+}
+
+func NewSettingsService(db *mongo.Database) *SettingsService {
+	return &SettingsService{
+		settingsCollection: db.Collection("user_settings"),
+	}
+}
+
+// GetSettings returns the settings of the user, or the defaults when the user
+// has not changed any.
+func (s *SettingsService) GetSettings(ctx context.Context, ownerID string) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	err := s.settingsCollection.FindOne(ctx, bson.M{"_id": ownerID}).Decode(&settings)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.DefaultUserSettings(ownerID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (s *SettingsService) UpdateSettings(ctx context.Context, ownerID string, input *dtos.UpdateUserSettingsInput) (*models.UserSettings, error) {
+	settings, err := s.GetSettings(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if input.AutoLinkJira != nil {
+		settings.AutoLinkJira = *input.AutoLinkJira
+	}
+	if input.FuzzyAutoLink != nil {
+		settings.FuzzyAutoLink = *input.FuzzyAutoLink
+	}
+	settings.UpdatedAt = time.Now()
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.settingsCollection.ReplaceOne(ctx, bson.M{"_id": ownerID}, settings, opts); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	SyncOperationCreate = "create"
+	SyncOperationUpdate = "update"
+	SyncOperationDelete = "delete"
+
+	SyncStatusPending = "pending"
+	SyncStatusFailed  = "failed"
+	SyncStatusDone    = "done"
+)
+
+const (
+	syncBaseBackoff = time.Minute
+	syncMaxBackoff  = 6 * time.Hour
+	syncMaxAttempts = 10
+	// syncLease is how long a claimed job is hidden from other workers.
+	syncLease = 5 * time.Minute
+)
+
+type SyncJobService struct {
+	syncJobCollection *mongo.Collection
+}
+
+func NewSyncJobService(db *mongo.Database) *SyncJobService {
+	return &SyncJobService{
+		syncJobCollection: db.Collection("sync_jobs"),
+	}
+}
+
+// Enqueue stores a job for later delivery. An open job for the same entry and
+// operation is reused, since the worker always sends the latest entry state.
+// A failed job is put back in the queue as if it was new, since the entry
+// changed after it gave up.
+func (s *SyncJobService) Enqueue(ctx context.Context, job *models.SyncJob, cause error) error {
+	now := time.Now()
+	filter := bson.M{
+		"time_entry_id": job.TimeEntryID,
+		"operation":     job.Operation,
+		"status":        bson.M{"$ne": SyncStatusDone},
+	}
+	var existing models.SyncJob
+	err := s.syncJobCollection.FindOne(ctx, filter).Decode(&existing)
+	if err == nil {
+		*job = existing
+		if job.Status != SyncStatusFailed {
+			return nil
+		}
+		job.Status = SyncStatusPending
+		job.Attempts = 1
+		if cause != nil {
+			job.LastError = cause.Error()
+		}
+		job.NextAttemptAt = now.Add(syncBackoff(job.Attempts))
+		job.UpdatedAt = now
+		_, err = s.syncJobCollection.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": bson.M{
+			"status":          job.Status,
+			"attempts":        job.Attempts,
+			"last_error":      job.LastError,
+			"next_attempt_at": job.NextAttemptAt,
+			"updated_at":      now,
+		}})
+		return err
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	job.ID = uuid.New().String()
+	job.Status = SyncStatusPending
+	job.Attempts = 1
+	if cause != nil {
+		job.LastError = cause.Error()
+	}
+	job.NextAttemptAt = now.Add(syncBackoff(job.Attempts))
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	_, err = s.syncJobCollection.InsertOne(ctx, job)
+	return err
+}
+
+// ClaimDueJob leases the next pending job whose retry time has passed, or
+// returns mongo.ErrNoDocuments when nothing is due.
+func (s *SyncJobService) ClaimDueJob(ctx context.Context) (*models.SyncJob, error) {
+	now := time.Now()
+	filter := bson.M{"status": SyncStatusPending, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(syncLease), "updated_at": now}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job models.SyncJob
+	if err := s.syncJobCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *SyncJobService) MarkDone(ctx context.Context, id string) error {
+	now := time.Now()
+	_, err := s.syncJobCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":       SyncStatusDone,
+		"completed_at": now,
+		"updated_at":   now,
+	}})
+	return err
+}
+
+// MarkAttemptFailed records the error and schedules the next attempt with
+// exponential backoff. After syncMaxAttempts the job is marked as failed.
+func (s *SyncJobService) MarkAttemptFailed(ctx context.Context, job *models.SyncJob, cause error) error {
+	now := time.Now()
+	job.Attempts++
+	job.LastError = cause.Error()
+	job.NextAttemptAt = now.Add(syncBackoff(job.Attempts))
+	job.Status = SyncStatusPending
+	if job.Attempts >= syncMaxAttempts {
+		job.Status = SyncStatusFailed
+	}
+	job.UpdatedAt = now
+	_, err := s.syncJobCollection.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": bson.M{
+		"status":          job.Status,
+		"attempts":        job.Attempts,
+		"last_error":      job.LastError,
+		"next_attempt_at": job.NextAttemptAt,
+		"updated_at":      now,
+	}})
+	return err
+}
+
+// GetOpenJobs lists the jobs of the user that have not been delivered yet.
+func (s *SyncJobService) GetOpenJobs(ctx context.Context, ownerID string, status string) ([]models.SyncJob, error) {
+	filter := bson.M{"owner_id": ownerID, "status": bson.M{"$ne": SyncStatusDone}}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := s.syncJobCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	jobs := []models.SyncJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// RetryJob puts a job of the user back in the queue for immediate delivery.
+func (s *SyncJobService) RetryJob(ctx context.Context, id string, ownerID string) error {
+	now := time.Now()
+	filter := bson.M{"_id": id, "owner_id": ownerID, "status": bson.M{"$ne": SyncStatusDone}}
+	res, err := s.syncJobCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"status":          SyncStatusPending,
+		"attempts":        0,
+		"next_attempt_at": now,
+		"updated_at":      now,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func syncBackoff(attempts int) time.Duration {
+	backoff := syncBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= syncMaxBackoff {
+			return syncMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SyncWorker delivers queued worklog operations in the background.
+type SyncWorker struct {
+	syncJobService   *SyncJobService
+	timeEntryService *TimeEntryService
+	interval         time.Duration
+}
+
+func NewSyncWorker(sjs *SyncJobService, tes *TimeEntryService, interval time.Duration) *SyncWorker {
+	return &SyncWorker{
+		syncJobService:   sjs,
+		timeEntryService: tes,
+		interval:         interval,
+	}
+}
+
+// Run processes due jobs every interval until the context is cancelled.
+func (w *SyncWorker) Run(ctx context.Context) {
+	log.Printf("Sync worker started, polling every %s", w.interval)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.processDueJobs(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("Sync worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *SyncWorker) processDueJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.syncJobService.ClaimDueJob(ctx)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				log.Printf("Error claiming sync job: %v", err)
+			}
+			return
+		}
+
+		if err := w.timeEntryService.ProcessSyncJob(ctx, job); err != nil {
+			log.Printf("Sync job %s (%s of time entry %s) failed: %v", job.ID, job.Operation, job.TimeEntryID, err)
+			if err := w.syncJobService.MarkAttemptFailed(ctx, job, err); err != nil {
+				log.Printf("Error updating sync job %s: %v", job.ID, err)
+			}
+			continue
+		}
+
+		if err := w.syncJobService.MarkDone(ctx, job.ID); err != nil {
+			log.Printf("Error completing sync job %s: %v", job.ID, err)
+		}
+	}
+}
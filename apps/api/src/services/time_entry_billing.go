@@ -0,0 +1,129 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// addBillable adds the billable time and amounts of the entries matching the
+// filter to the statistics. Each entry is charged at the rate that applied to
+// it when it started, so rate changes do not alter past months.
+func (s *TimeEntryService) addBillable(ctx context.Context, filter bson.M, stats *models.TimeEntryStatistics) error {
+	stats.BillablePerProject = []models.TimeEntryBillable{}
+	stats.BillableTotals = []models.BillableTotal{}
+
+	billableFilter := bson.M{"billable": true}
+	for key, value := range filter {
+		billableFilter[key] = value
+	}
+	opts := options.Find().SetProjection(bson.M{"project_id": 1, "owner_id": 1, "period": 1})
+	cursor, err := s.timeEntryCollection.Find(ctx, billableFilter, opts)
+	if err != nil {
+		return err
+	}
+	var entries []models.TimeEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if !seen[entry.ProjectID] {
+			seen[entry.ProjectID] = true
+			projectIDs = append(projectIDs, entry.ProjectID)
+		}
+	}
+	projectList, err := s.projectService.GetProjectsByIDs(ctx, projectIDs)
+	if err != nil {
+		return err
+	}
+	projects := make(map[string]*models.Project, len(projectList))
+	for i := range projectList {
+		projects[projectList[i].ID] = &projectList[i]
+	}
+
+	// Personal projects use the rates of their owner, workspace projects the
+	// rates of the workspace.
+	tables := make(map[string]rateTable)
+	type billableKey struct{ projectID, currency string }
+	// Amounts are summed as rate times seconds and divided by an hour at the
+	// end, so rounding happens once per project and currency.
+	sums := make(map[billableKey]*models.TimeEntryBillable)
+	for i := range entries {
+		entry := &entries[i]
+		key := billableKey{projectID: entry.ProjectID}
+		var rate *models.Rate
+		if project := projects[entry.ProjectID]; project != nil {
+			scope := project.WorkspaceID
+			if scope == "" {
+				scope = "user:" + project.OwnerID
+			}
+			table, ok := tables[scope]
+			if !ok {
+				rates, err := s.rateService.GetRates(ctx, project.OwnerID, project.WorkspaceID, nil)
+				if err != nil {
+					return err
+				}
+				table = rates
+				tables[scope] = table
+			}
+			rate = table.rateFor(entry, project.ClientID)
+		}
+		if rate != nil {
+			key.currency = rate.Currency
+		}
+
+		sum, ok := sums[key]
+		if !ok {
+			sum = &models.TimeEntryBillable{ProjectID: key.projectID, Currency: key.currency}
+			sums[key] = sum
+		}
+		sum.BillableTime += int64(entry.Period.Duration)
+		if rate != nil {
+			sum.Amount += int64(entry.Period.Duration) * rate.HourlyRate
+		}
+		stats.BillableTime += int64(entry.Period.Duration)
+	}
+
+	totals := make(map[string]*models.BillableTotal)
+	for _, sum := range sums {
+		sum.Amount = (sum.Amount + 1800) / 3600
+		stats.BillablePerProject = append(stats.BillablePerProject, *sum)
+		if sum.Currency == "" {
+			continue
+		}
+		total, ok := totals[sum.Currency]
+		if !ok {
+			total = &models.BillableTotal{Currency: sum.Currency}
+			totals[sum.Currency] = total
+		}
+		total.BillableTime += sum.BillableTime
+		total.Amount += sum.Amount
+	}
+	for _, total := range totals {
+		stats.BillableTotals = append(stats.BillableTotals, *total)
+	}
+
+	sort.Slice(stats.BillablePerProject, func(i, j int) bool {
+		a, b := stats.BillablePerProject[i], stats.BillablePerProject[j]
+		if a.Amount != b.Amount {
+			return a.Amount > b.Amount
+		}
+		if a.ProjectID != b.ProjectID {
+			return a.ProjectID < b.ProjectID
+		}
+		return a.Currency < b.Currency
+	})
+	sort.Slice(stats.BillableTotals, func(i, j int) bool {
+		return stats.BillableTotals[i].Currency < stats.BillableTotals[j].Currency
+	})
+	return nil
+}
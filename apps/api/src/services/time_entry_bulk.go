@@ -0,0 +1,205 @@
+package services
+
+import (
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const bulkTimeLayout = "2006-01-02 15:04"
+
+// BulkCreateTimeEntries validates every row and, unless it is a dry run or any
+// row is invalid, inserts all of them. Nothing is inserted when a row fails.
+// Projects are looked up and created in the workspace, or among the personal
+// projects when workspaceID is empty.
+func (s *TimeEntryService) BulkCreateTimeEntries(ctx context.Context, ownerID string, workspaceID string, input *dtos.BulkCreateTimeEntriesInput) (*models.BulkTimeEntriesResult, error) {
+	result := &models.BulkTimeEntriesResult{
+		DryRun: input.DryRun,
+		Rows:   make([]models.BulkTimeEntryRowResult, len(input.Entries)),
+	}
+
+	policy, err := s.userService.GetOverlapPolicy(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.userService.GetLocation(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Projects by lower case name. A nil project is one that will be created.
+	projects := make(map[string]*models.Project)
+	projectNames := make(map[string]string)
+
+	for i, row := range input.Entries {
+		res := &result.Rows[i]
+		res.Row = i
+
+		entry := &models.TimeEntry{
+			OwnerID: ownerID,
+			Note:    row.Note,
+			Period: models.TimePeriod{
+				Started:  row.Period.Start,
+				Ended:    row.Period.End,
+				Duration: int(row.Period.End.Sub(row.Period.Start).Seconds()),
+			},
+		}
+		res.Entry = entry
+		// Projects that do not exist yet are created in the workspace.
+		entryWorkspaceID := workspaceID
+
+		name := strings.TrimSpace(row.ProjectName)
+		if name == "" {
+			res.Errors = append(res.Errors, "project name is required")
+		} else {
+			key := strings.ToLower(name)
+			project, known := projects[key]
+			if !known {
+				found, err := s.projectService.GetProjectByName(ctx, ownerID, workspaceID, name)
+				if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+					return nil, err
+				}
+				project = found
+				projects[key] = found
+				projectNames[key] = name
+			}
+			if project != nil {
+				entry.ProjectID = project.ID
+				entry.Billable = project.Billable
+				entryWorkspaceID = project.WorkspaceID
+			} else if input.CreateProjects {
+				res.ProjectCreated = true
+			} else {
+				res.Errors = append(res.Errors, "project not found: "+name)
+			}
+		}
+
+		if len(row.Note) > 1024 {
+			res.Errors = append(res.Errors, "note must be at most 1024 characters")
+		}
+
+		if row.Period.Start.IsZero() || row.Period.End.IsZero() {
+			res.Errors = append(res.Errors, "start and end are required")
+			continue
+		}
+		if !row.Period.End.After(row.Period.Start) {
+			res.Errors = append(res.Errors, "end must be after start")
+			continue
+		}
+
+		if err := s.checkWeekLock(ctx, ownerID, entryWorkspaceID, row.Period.Start); err != nil {
+			if !errors.Is(err, ErrEntryLocked) {
+				return nil, err
+			}
+			res.Errors = append(res.Errors, err.Error())
+		}
+
+		if policy == models.OverlapPolicyAllow {
+			continue
+		}
+		overlapping, err := s.FindOverlappingEntries(ctx, ownerID, row.Period.Start, row.Period.End, "")
+		if err != nil {
+			return nil, err
+		}
+		if policy == models.OverlapPolicyWarn {
+			entry.Conflicts = overlapping
+			continue
+		}
+		for _, o := range overlapping {
+			res.Errors = append(res.Errors, fmt.Sprintf("overlaps existing entry from %s to %s",
+				o.Period.Started.In(loc).Format(bulkTimeLayout), o.Period.Ended.In(loc).Format(bulkTimeLayout)))
+		}
+	}
+
+	if policy == models.OverlapPolicyReject {
+		markBatchOverlaps(input.Entries, result.Rows)
+	}
+
+	for _, res := range result.Rows {
+		if len(res.Errors) > 0 {
+			result.Invalid++
+		} else {
+			result.Valid++
+		}
+	}
+	if input.DryRun || result.Invalid > 0 {
+		return result, nil
+	}
+
+	for key, project := range projects {
+		if project != nil {
+			continue
+		}
+		project = &models.Project{Name: projectNames[key], OwnerID: ownerID, WorkspaceID: workspaceID}
+		if err := s.projectService.CreateProject(ctx, project); err != nil {
+			return nil, err
+		}
+		projects[key] = project
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(result.Rows))
+	for i := range result.Rows {
+		entry := result.Rows[i].Entry
+		project := projects[strings.ToLower(strings.TrimSpace(input.Entries[i].ProjectName))]
+		entry.ID = uuid.New().String()
+		entry.ProjectID = project.ID
+		entry.CreatedAt = now
+		entry.UpdatedAt = now
+		docs = append(docs, entry)
+	}
+	if _, err := s.timeEntryCollection.InsertMany(ctx, docs); err != nil {
+		return nil, err
+	}
+	result.Inserted = len(docs)
+
+	if input.Report {
+		for i := range result.Rows {
+			project := projects[strings.ToLower(strings.TrimSpace(input.Entries[i].ProjectName))]
+			s.reportStoredEntry(ctx, result.Rows[i].Entry, project)
+		}
+	}
+	return result, nil
+}
+
+// markBatchOverlaps flags rows of the same request that overlap each other.
+// The rows are referred to by their index in the request, like Row, so the
+// client can map them to its own line numbers.
+func markBatchOverlaps(rows []dtos.BulkTimeEntryRow, results []models.BulkTimeEntryRowResult) {
+	order := make([]int, 0, len(rows))
+	for i, row := range rows {
+		if !row.Period.Start.IsZero() && row.Period.End.After(row.Period.Start) {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return rows[order[a]].Period.Start.Before(rows[order[b]].Period.Start)
+	})
+
+	latest := -1 // row with the latest end seen so far
+	for _, i := range order {
+		if latest >= 0 && rows[i].Period.Start.Before(rows[latest].Period.End) {
+			markBatchOverlap(&results[i], latest)
+			markBatchOverlap(&results[latest], i)
+		}
+		if latest < 0 || rows[i].Period.End.After(rows[latest].Period.End) {
+			latest = i
+		}
+	}
+}
+
+func markBatchOverlap(res *models.BulkTimeEntryRowResult, row int) {
+	if len(res.Overlaps) == 0 {
+		res.Errors = append(res.Errors, models.BulkRowOverlapError)
+	}
+	res.Overlaps = append(res.Overlaps, row)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidExportFormat = errors.New("invalid export format, must be one of 'csv' or 'xlsx'")
+
+// ExportContentTypes maps the supported export formats to their MIME type.
+var ExportContentTypes = map[string]string{
+	"csv":  "text/csv; charset=utf-8",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var timeEntryExportHeader = []interface{}{"Project", "Issue Key", "Start", "End", "Duration (h)", "Duration (s)", "Note"}
+
+const exportTimeLayout = "2006-01-02 15:04"
+
+type exportRowWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRowWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		if text, ok := v.(string); ok {
+			record[i] = escapeCSVFormula(text)
+			continue
+		}
+		record[i] = fmt.Sprint(v)
+	}
+	return c.w.Write(record)
+}
+
+// escapeCSVFormula prefixes text that a spreadsheet would read as a formula
+// with a single quote, so user input such as notes is shown as written.
+func escapeCSVFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ExportTimeEntries streams every matching entry of the user to w, oldest
+// first, in the given format. Times are written in the user's timezone.
+func (s *TimeEntryService) ExportTimeEntries(ctx context.Context, w io.Writer, ownerID string, from, to *time.Time, format string) error {
+	loc, err := s.userService.GetLocation(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	var rows exportRowWriter
+	switch format {
+	case "csv":
+		rows = &csvRowWriter{w: csv.NewWriter(w)}
+	case "xlsx":
+		xw, err := newXLSXWriter(w, "Time Entries")
+		if err != nil {
+			return err
+		}
+		rows = xw
+	default:
+		return ErrInvalidExportFormat
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "period.started", Value: 1}})
+	cursor, err := s.timeEntryCollection.Find(ctx, timeEntryFilter(ownerID, from, to), opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	if err := rows.WriteRow(timeEntryExportHeader); err != nil {
+		return err
+	}
+
+	projects := make(map[string]*models.Project)
+	for cursor.Next(ctx) {
+		var entry models.TimeEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+
+		project, ok := projects[entry.ProjectID]
+		if !ok {
+			project, _ = s.projectService.GetProjectByID(ctx, entry.ProjectID, ownerID)
+			projects[entry.ProjectID] = project
+		}
+		projectName, issueKey := entry.ProjectID, ""
+		if project != nil {
+			projectName = project.Name
+			issueKey = project.Integration.Key
+		}
+
+		hours := float64(entry.Period.Duration) / 3600
+		err := rows.WriteRow([]interface{}{
+			projectName,
+			issueKey,
+			entry.Period.Started.In(loc).Format(exportTimeLayout),
+			entry.Period.Ended.In(loc).Format(exportTimeLayout),
+			float64(int(hours*100+0.5)) / 100,
+			entry.Period.Duration,
+			entry.Note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return rows.Close()
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TimeEntryFilter narrows the entries listed by GetTimeEntries. Fields left at
+// their zero value do not filter.
+type TimeEntryFilter struct {
+	From        *time.Time
+	To          *time.Time
+	ProjectIDs  []string
+	Text        string   // words of the note, matched with the text index
+	Reported    *bool    // whether the entry was reported to the integration of its project
+	Integration string   // integration type of the project, e.g. "jira"
+	MinDuration int      // in seconds
+	MaxDuration int      // in seconds
+	Tags        []string // entries must have all of them
+}
+
+// EnsureIndexes creates the text index on the notes that the Text filter
+// searches, and the unique index that allows one running timer per user.
+// Creating an index that already exists does nothing.
+func (s *TimeEntryService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.timeEntryCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "note", Value: "text"}},
+			Options: options.Index().SetName("note_text"),
+		},
+		{
+			// Partial indexes cannot filter on a missing period.ended, so
+			// running timers carry a flag instead.
+			Keys: bson.D{{Key: "owner_id", Value: 1}},
+			Options: options.Index().
+				SetName("owner_id_running").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"running": true}),
+		},
+	})
+	return err
+}
+
+// listFilter returns the query for the entries of the owner that match f.
+func (s *TimeEntryService) listFilter(ctx context.Context, ownerID string, f TimeEntryFilter) (bson.M, error) {
+	filter := timeEntryFilter(ownerID, f.From, f.To)
+
+	if len(f.ProjectIDs) > 0 || f.Integration != "" {
+		projectIDs := f.ProjectIDs
+		if f.Integration != "" {
+			linked, err := s.projectService.GetIntegrationProjectIDs(ctx, ownerID, f.Integration)
+			if err != nil {
+				return nil, err
+			}
+			if len(f.ProjectIDs) == 0 {
+				projectIDs = linked
+			} else {
+				projectIDs = intersectIDs(f.ProjectIDs, linked)
+			}
+		}
+		filter["project_id"] = bson.M{"$in": projectIDs}
+	}
+	if f.Text != "" {
+		filter["$text"] = bson.M{"$search": f.Text}
+	}
+	if f.Reported != nil {
+		if *f.Reported {
+			filter["reported.done"] = true
+		} else {
+			filter["reported.done"] = bson.M{"$ne": true}
+		}
+	}
+	if f.MinDuration > 0 || f.MaxDuration > 0 {
+		duration := bson.M{}
+		if f.MinDuration > 0 {
+			duration["$gte"] = f.MinDuration
+		}
+		if f.MaxDuration > 0 {
+			duration["$lte"] = f.MaxDuration
+		}
+		filter["period.duration"] = duration
+	}
+	if len(f.Tags) > 0 {
+		filter["tags"] = bson.M{"$all": f.Tags}
+	}
+	return filter, nil
+}
+
+// intersectIDs returns the IDs of a that are also in b.
+func intersectIDs(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, id := range b {
+		inB[id] = true
+	}
+	ids := []string{}
+	for _, id := range a {
+		if inB[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// GetEntryProjectIDs returns the projects of the finished entries of the user
+// that started within the optional date range.
+func (s *TimeEntryService) GetEntryProjectIDs(ctx context.Context, ownerID string, from, to *time.Time) ([]string, error) {
+	values, err := s.timeEntryCollection.Distinct(ctx, "project_id", timeEntryFilter(ownerID, from, to))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrReconcileUnsupported = errors.New("integration does not support reconciliation")
+	ErrDriftChanged         = errors.New("the difference changed since it was reported, reconcile again")
+)
+
+// reconcileTolerance is the largest duration difference in seconds that is not
+// reported as drift. Jira rounds logged time to whole minutes.
+const reconcileTolerance = 60
+
+func (s *TimeEntryService) worklogLister(integration string) (IntegrationProvider, WorklogLister, error) {
+	provider, ok := s.integrations.Get(integration)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownIntegration, integration)
+	}
+	lister, ok := provider.(WorklogLister)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrReconcileUnsupported, integration)
+	}
+	return provider, lister, nil
+}
+
+// ReconcileWorklogs compares the entries of the user that are reported to the
+// integration and started within the range with the user's worklogs on the
+// linked issues. The drift found is also recorded on the ReportStatus of the
+// entries.
+func (s *TimeEntryService) ReconcileWorklogs(ctx context.Context, ownerID string, integration string, from, to time.Time) (*models.ReconcileReport, error) {
+	_, lister, err := s.worklogLister(integration)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconcileReport{
+		Integration: integration,
+		From:        from,
+		To:          to,
+		Drift:       []models.WorklogDrift{},
+	}
+
+	projects, err := s.projectService.GetLinkedProjects(ctx, ownerID, integration)
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return report, nil
+	}
+	projectByID := make(map[string]*models.Project, len(projects))
+	projectByIssue := make(map[string]*models.Project, len(projects))
+	projectIDs := make([]string, 0, len(projects))
+	issueIDs := make([]string, 0, len(projects))
+	for i := range projects {
+		project := &projects[i]
+		projectByID[project.ID] = project
+		projectIDs = append(projectIDs, project.ID)
+		if _, seen := projectByIssue[project.Integration.ExternalID]; !seen {
+			projectByIssue[project.Integration.ExternalID] = project
+			issueIDs = append(issueIDs, project.Integration.ExternalID)
+		}
+	}
+
+	worklogs, err := lister.ListWorklogs(ownerID, issueIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report.Worklogs = len(worklogs)
+	worklogByID := make(map[string]models.Worklog, len(worklogs))
+	for _, worklog := range worklogs {
+		worklogByID[worklog.ID] = worklog
+	}
+
+	filter := timeEntryFilter(ownerID, &from, &to)
+	filter["project_id"] = bson.M{"$in": projectIDs}
+	filter["reported.done"] = true
+	filter["reported.integration"] = integration
+	cursor, err := s.timeEntryCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var entries []models.TimeEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	report.Entries = len(entries)
+
+	matched := make(map[string]bool, len(entries))
+	var inSync []string
+	for i := range entries {
+		entry := &entries[i]
+		issueID := projectByID[entry.ProjectID].Integration.ExternalID
+
+		worklog, ok := worklogByID[entry.Reported.ExternalID]
+		if !ok {
+			// The worklog may have moved out of the range, so look it up directly
+			// before calling it missing.
+			found, err := lister.GetWorklog(ownerID, issueID, entry.Reported.ExternalID)
+			if errors.Is(err, ErrWorklogNotFound) {
+				report.Drift = append(report.Drift, models.WorklogDrift{
+					Kind:      models.DriftMissing,
+					ProjectID: entry.ProjectID,
+					IssueID:   issueID,
+					TimeEntry: entry,
+				})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			worklog = *found
+		}
+		matched[worklog.ID] = true
+
+		if durationDrifted(entry, &worklog) {
+			report.Drift = append(report.Drift, models.WorklogDrift{
+				Kind:      models.DriftChanged,
+				ProjectID: entry.ProjectID,
+				IssueID:   issueID,
+				TimeEntry: entry,
+				Worklog:   &worklog,
+			})
+			continue
+		}
+		inSync = append(inSync, entry.ID)
+	}
+
+	// Worklogs of entries that started outside the range are not orphans.
+	var unmatched []string
+	for _, worklog := range worklogs {
+		if !matched[worklog.ID] {
+			unmatched = append(unmatched, worklog.ID)
+		}
+	}
+	linked, err := s.linkedWorklogIDs(ctx, ownerID, integration, unmatched)
+	if err != nil {
+		return nil, err
+	}
+	for i := range worklogs {
+		worklog := &worklogs[i]
+		if matched[worklog.ID] || linked[worklog.ID] {
+			continue
+		}
+		report.Drift = append(report.Drift, models.WorklogDrift{
+			Kind:      models.DriftOrphaned,
+			ProjectID: projectByIssue[worklog.IssueID].ID,
+			IssueID:   worklog.IssueID,
+			Worklog:   worklog,
+		})
+	}
+
+	if err := s.markDrift(ctx, report.Drift, inSync); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func durationDrifted(entry *models.TimeEntry, worklog *models.Worklog) bool {
+	diff := entry.Period.Duration - worklog.Duration
+	return diff >= reconcileTolerance || diff <= -reconcileTolerance
+}
+
+// linkedWorklogIDs returns which of the worklogs belong to an entry of the user.
+func (s *TimeEntryService) linkedWorklogIDs(ctx context.Context, ownerID string, integration string, worklogIDs []string) (map[string]bool, error) {
+	linked := make(map[string]bool)
+	if len(worklogIDs) == 0 {
+		return linked, nil
+	}
+	filter := bson.M{
+		"owner_id":             ownerID,
+		"deleted_at":           bson.M{"$eq": nil},
+		"reported.integration": integration,
+		"reported.external_id": bson.M{"$in": worklogIDs},
+	}
+	cursor, err := s.timeEntryCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var entries []models.TimeEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		linked[entry.Reported.ExternalID] = true
+	}
+	return linked, nil
+}
+
+// markDrift records the result of a reconciliation on the reported entries.
+func (s *TimeEntryService) markDrift(ctx context.Context, drift []models.WorklogDrift, inSync []string) error {
+	now := time.Now()
+	for _, d := range drift {
+		if d.TimeEntry == nil {
+			continue
+		}
+		d.TimeEntry.Reported.Drift = d.Kind
+		d.TimeEntry.Reported.CheckedAt = &now
+		_, err := s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": d.TimeEntry.ID}, bson.M{"$set": bson.M{
+			"reported.drift":      d.Kind,
+			"reported.checked_at": now,
+		}})
+		if err != nil {
+			return err
+		}
+	}
+	if len(inSync) == 0 {
+		return nil
+	}
+	_, err := s.timeEntryCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": inSync}}, bson.M{
+		"$set":   bson.M{"reported.checked_at": now},
+		"$unset": bson.M{"reported.drift": ""},
+	})
+	return err
+}
+
+// ResolveDrift pulls or pushes each difference. Every item is checked against
+// the integration again first, so a stale report cannot overwrite newer changes.
+func (s *TimeEntryService) ResolveDrift(ctx context.Context, ownerID string, integration string, items []dtos.DriftResolution) ([]models.DriftResolutionResult, error) {
+	provider, lister, err := s.worklogLister(integration)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.DriftResolutionResult, 0, len(items))
+	for _, item := range items {
+		result := models.DriftResolutionResult{
+			Kind:        item.Kind,
+			Action:      item.Action,
+			TimeEntryID: item.TimeEntryID,
+			WorklogID:   item.WorklogID,
+		}
+		var entry *models.TimeEntry
+		if item.Kind == models.DriftOrphaned {
+			entry, err = s.resolveOrphanedWorklog(ctx, ownerID, provider, lister, item)
+		} else {
+			entry, err = s.resolveEntryDrift(ctx, ownerID, provider, lister, item)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if entry != nil {
+			result.TimeEntryID = entry.ID
+			result.TimeEntry = entry
+			if entry.Reported != nil {
+				result.WorklogID = entry.Reported.ExternalID
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// resolveEntryDrift handles missing and changed worklogs of a reported entry.
+// It returns the entry after the change, or nil when it was deleted.
+func (s *TimeEntryService) resolveEntryDrift(ctx context.Context, ownerID string, provider IntegrationProvider, lister WorklogLister, item dtos.DriftResolution) (*models.TimeEntry, error) {
+	entry, err := s.GetTimeEntryByID(ctx, item.TimeEntryID, ownerID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("time entry not found")
+		}
+		return nil, err
+	}
+	if entry.Reported == nil || !entry.Reported.Done || entry.Reported.Integration != provider.Type() {
+		return nil, errors.New("time entry is not reported to " + provider.Type())
+	}
+	project, err := s.projectService.GetProjectByID(ctx, entry.ProjectID, ownerID)
+	if err != nil || project.Integration.Type != provider.Type() {
+		return nil, errors.New("project is not linked to " + provider.Type())
+	}
+	issueID := project.Integration.ExternalID
+
+	worklog, err := lister.GetWorklog(ownerID, issueID, entry.Reported.ExternalID)
+	kind := models.DriftChanged
+	switch {
+	case errors.Is(err, ErrWorklogNotFound):
+		kind = models.DriftMissing
+	case err != nil:
+		return nil, err
+	case !durationDrifted(entry, worklog):
+		kind = ""
+	}
+	if kind != item.Kind {
+		return nil, ErrDriftChanged
+	}
+	if item.Action == "pull" {
+		if err := s.checkTimesheetLock(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	update := bson.M{}
+	switch {
+	case kind == models.DriftMissing && item.Action == "pull":
+		// The worklog was deleted in the integration, so delete the entry too.
+		_, err := s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": entry.ID}, bson.M{"$set": bson.M{"deleted_at": now}})
+		return nil, err
+	case kind == models.DriftMissing && item.Action == "push":
+		entry.Reported = &models.ReportStatus{Integration: provider.Type()}
+		if err := s.syncTimeEntry(entry, SyncOperationCreate, issueID); err != nil {
+			return nil, err
+		}
+	case kind == models.DriftChanged && item.Action == "pull":
+		entry.Period.Duration = worklog.Duration
+		entry.Period.Ended = entry.Period.Started.Add(time.Duration(worklog.Duration) * time.Second)
+		conflicts, err := s.checkOverlap(ctx, entry, entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		entry.Conflicts = conflicts
+		entry.UpdatedAt = now
+		update["period"] = entry.Period
+		update["updated_at"] = now
+	case kind == models.DriftChanged && item.Action == "push":
+		if err := s.syncTimeEntry(entry, SyncOperationUpdate, issueID); err != nil {
+			return nil, err
+		}
+	}
+
+	entry.Reported.Drift = ""
+	entry.Reported.CheckedAt = &now
+	update["reported"] = entry.Reported
+	if _, err := s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": entry.ID}, bson.M{"$set": update}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// resolveOrphanedWorklog imports a worklog that has no entry, or deletes it
+// from the integration. It returns the imported entry. Like a created entry,
+// the import follows the overlap policy of the user.
+func (s *TimeEntryService) resolveOrphanedWorklog(ctx context.Context, ownerID string, provider IntegrationProvider, lister WorklogLister, item dtos.DriftResolution) (*models.TimeEntry, error) {
+	if item.ProjectID == "" || item.WorklogID == "" {
+		return nil, errors.New("project_id and worklog_id are required for orphaned worklogs")
+	}
+	project, err := s.projectService.GetProjectByID(ctx, item.ProjectID, ownerID)
+	if err != nil || project.Integration.Type != provider.Type() {
+		return nil, errors.New("project is not linked to " + provider.Type())
+	}
+	issueID := project.Integration.ExternalID
+
+	worklog, err := lister.GetWorklog(ownerID, issueID, item.WorklogID)
+	if err != nil {
+		if errors.Is(err, ErrWorklogNotFound) {
+			return nil, ErrDriftChanged
+		}
+		return nil, err
+	}
+	linked, err := s.linkedWorklogIDs(ctx, ownerID, provider.Type(), []string{worklog.ID})
+	if err != nil {
+		return nil, err
+	}
+	if linked[worklog.ID] {
+		return nil, ErrDriftChanged
+	}
+
+	if item.Action == "push" {
+		// The entry does not exist locally, so remove the worklog.
+		return nil, provider.DeleteWorklog(ownerID, issueID, worklog.ID)
+	}
+
+	now := time.Now()
+	entry := &models.TimeEntry{
+		ID:        uuid.New().String(),
+		ProjectID: project.ID,
+		OwnerID:   ownerID,
+		Period: models.TimePeriod{
+			Started:  worklog.Started,
+			Ended:    worklog.Started.Add(time.Duration(worklog.Duration) * time.Second),
+			Duration: worklog.Duration,
+		},
+		Note:     worklog.Comment,
+		Billable: project.Billable,
+		Reported: &models.ReportStatus{
+			Done:        true,
+			Integration: provider.Type(),
+			ExternalID:  worklog.ID,
+			ReportedAt:  &worklog.Started,
+			UpdatedAt:   &now,
+			CheckedAt:   &now,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.checkWeekLock(ctx, ownerID, project.WorkspaceID, entry.Period.Started); err != nil {
+		return nil, err
+	}
+	conflicts, err := s.checkOverlap(ctx, entry, "")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.timeEntryCollection.InsertOne(ctx, entry); err != nil {
+		return nil, err
+	}
+	entry.Conflicts = conflicts
+	return entry, nil
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrInvalidMonth        = errors.New("invalid month, must be like 2026-09")
+	ErrInvalidReportFormat = errors.New("invalid report format, must be one of 'pdf' or 'html'")
+)
+
+// ReportContentTypes maps the supported report formats to their MIME type.
+var ReportContentTypes = map[string]string{
+	"pdf":  "application/pdf",
+	"html": "text/html; charset=utf-8",
+}
+
+// MonthlyReport is the time logged in a month, grouped by project and day,
+// with the totals and billable amounts of the statistics.
+type MonthlyReport struct {
+	Month          string // e.g. "2026-09"
+	Start          time.Time
+	End            time.Time // exclusive, the first of the next month
+	Timezone       string
+	Client         *models.Client // nil unless the report is for a single client
+	Team           bool           // the entries of several users, so each entry names its user
+	Projects       []ReportProject
+	TotalEntries   int64
+	TotalTime      int64
+	BillableTime   int64
+	BillableTotals []models.BillableTotal
+	GeneratedAt    time.Time
+}
+
+type ReportProject struct {
+	ID        string
+	Name      string
+	IssueKey  string
+	Days      []ReportDay
+	TotalTime int64
+	Billable  []models.TimeEntryBillable
+}
+
+type ReportDay struct {
+	Date      time.Time // midnight in the timezone of the report
+	Entries   []ReportEntry
+	TotalTime int64
+}
+
+type ReportEntry struct {
+	Start    time.Time // in the timezone of the report
+	End      time.Time
+	Duration int64
+	Note     string
+	User     string // email of the owner, set in team reports
+	Billable bool
+}
+
+// GetMonthlyReport returns the report of the month, such as "2026-09", in the
+// timezone of the viewer. Without members it covers the viewer's own entries,
+// otherwise the entries of the members on the projects of the workspace. A
+// client limits it to the projects billed to the client.
+func (s *TimeEntryService) GetMonthlyReport(
+	ctx context.Context,
+	viewerID string,
+	workspaceID string,
+	members []models.WorkspaceMember,
+	client *models.Client,
+	month string,
+) (*MonthlyReport, error) {
+	loc, err := s.userService.GetLocation(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.ParseInLocation("2006-01", month, loc)
+	if err != nil {
+		return nil, ErrInvalidMonth
+	}
+	end := start.AddDate(0, 1, 0)
+
+	filter := timeEntryFilter(viewerID, nil, nil)
+	filter["period.started"] = bson.M{"$gte": start, "$lt": end}
+	emails := make(map[string]string)
+	if members != nil {
+		memberIDs := make([]string, len(members))
+		for i, member := range members {
+			memberIDs[i] = member.UserID
+			emails[member.UserID] = member.Email
+		}
+		filter["owner_id"] = bson.M{"$in": memberIDs}
+		projectIDs, err := s.projectService.GetWorkspaceProjectIDs(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		filter["project_id"] = bson.M{"$in": projectIDs}
+	}
+	if client != nil {
+		clientProjectIDs, err := s.projectService.GetClientProjectIDs(ctx, client.ID)
+		if err != nil {
+			return nil, err
+		}
+		// The client is from the same workspace, so its projects are too.
+		filter["project_id"] = bson.M{"$in": clientProjectIDs}
+	}
+
+	stats, err := s.aggregateStatistics(ctx, filter, loc, "d")
+	if err != nil {
+		return nil, err
+	}
+	report := &MonthlyReport{
+		Month:          month,
+		Start:          start,
+		End:            end,
+		Timezone:       loc.String(),
+		Client:         client,
+		Team:           members != nil,
+		Projects:       []ReportProject{},
+		TotalEntries:   stats.TotalEntries,
+		TotalTime:      stats.TotalTime,
+		BillableTime:   stats.BillableTime,
+		BillableTotals: stats.BillableTotals,
+		GeneratedAt:    time.Now().In(loc),
+	}
+	if len(stats.EntriesPerProject) == 0 {
+		return report, nil
+	}
+
+	projectIDs := make([]string, len(stats.EntriesPerProject))
+	for i, total := range stats.EntriesPerProject {
+		projectIDs[i] = total.ProjectID
+	}
+	projectList, err := s.projectService.GetProjectsByIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	projects := make(map[string]*models.Project, len(projectList))
+	for i := range projectList {
+		projects[projectList[i].ID] = &projectList[i]
+	}
+	byProject := make(map[string]*ReportProject, len(stats.EntriesPerProject))
+	for _, total := range stats.EntriesPerProject {
+		project := ReportProject{ID: total.ProjectID, Name: total.ProjectID, TotalTime: int64(total.TotalTime)}
+		if p := projects[total.ProjectID]; p != nil {
+			project.Name = p.Name
+			project.IssueKey = p.Integration.Key
+		}
+		for _, billable := range stats.BillablePerProject {
+			if billable.ProjectID == total.ProjectID {
+				project.Billable = append(project.Billable, billable)
+			}
+		}
+		report.Projects = append(report.Projects, project)
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		return strings.ToLower(report.Projects[i].Name) < strings.ToLower(report.Projects[j].Name)
+	})
+	for i := range report.Projects {
+		byProject[report.Projects[i].ID] = &report.Projects[i]
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "period.started", Value: 1}})
+	cursor, err := s.timeEntryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+	for cursor.Next(ctx) {
+		var entry models.TimeEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		project := byProject[entry.ProjectID]
+		if project == nil {
+			continue
+		}
+		started := entry.Period.Started.In(loc)
+		date := time.Date(started.Year(), started.Month(), started.Day(), 0, 0, 0, 0, loc)
+		if n := len(project.Days); n == 0 || !project.Days[n-1].Date.Equal(date) {
+			project.Days = append(project.Days, ReportDay{Date: date})
+		}
+		day := &project.Days[len(project.Days)-1]
+		day.Entries = append(day.Entries, ReportEntry{
+			Start:    started,
+			End:      entry.Period.Ended.In(loc),
+			Duration: int64(entry.Period.Duration),
+			Note:     entry.Note,
+			User:     emails[entry.OwnerID],
+			Billable: entry.Billable,
+		})
+		day.TotalTime += int64(entry.Period.Duration)
+	}
+	return report, cursor.Err()
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"TimeTrack-shared/models"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -10,19 +11,43 @@ import (
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+    ErrTimerAlreadyRunning = errors.New("a timer is already running")
+    ErrNoTimerRunning      = errors.New("no timer is running")
+)
+
+// OverlapError is returned when an entry overlaps other entries of the same
+// user and the user's overlap policy rejects it.
+type OverlapError struct {
+    Conflicts []models.TimeEntry
+}
+
+func (e *OverlapError) Error() string {
+    return fmt.Sprintf("time entry overlaps %d existing entries", len(e.Conflicts))
+}
+
 type TimeEntryService struct {
     timeEntryCollection *mongo.Collection
     projectService      *ProjectService
-    atlassianService    *AtlassianService
+    integrations        *IntegrationRegistry
+    syncJobService      *SyncJobService
+    userService         *UserService
+    timesheetService    *TimesheetService
+    rateService         *RateService
 }
 
-func NewTimeEntryService(db *mongo.Database, ps *ProjectService, as *AtlassianService) *TimeEntryService {
+func NewTimeEntryService(db *mongo.Database, ps *ProjectService, ir *IntegrationRegistry, sjs *SyncJobService, us *UserService, ts *TimesheetService, rs *RateService) *TimeEntryService {
     return &TimeEntryService{
         timeEntryCollection: db.Collection("time_entries"),
         projectService:      ps,
-        atlassianService:    as,
+        integrations:        ir,
+        syncJobService:      sjs,
+        userService:         us,
+        timesheetService:    ts,
+        rateService:         rs,
     }
 }
 
@@ -32,32 +57,36 @@ func (s *TimeEntryService) CreateTimeEntry(ctx context.Context, entry *models.Ti
         log.Println("Error getting project:", err)
         return err
     }
-    if project.Integration.Type == "jira" {
-        timeEntryId, err := s.atlassianService.AddTimeEntryToJira(entry, project.Integration.ExternalID)
-        if err == nil {
-            now := time.Now()
-            entry.Reported = &models.ReportStatus{
-                Done:        true,
-                Integration: project.Integration.Type,
-                ExternalID:  timeEntryId,
-                ReportedAt:  &entry.Period.Started,
-                UpdatedAt:   &now,
-            }
-        }
+    if err := s.checkTimesheetLock(ctx, entry); err != nil {
+        return err
+    }
+    conflicts, err := s.checkOverlap(ctx, entry, "")
+    if err != nil {
+        return err
     }
     entry.ID = uuid.New().String()
     entry.CreatedAt = time.Now()
     entry.UpdatedAt = time.Now()
     entry.DeletedAt = nil
     _, err = s.timeEntryCollection.InsertOne(ctx, entry)
-    return err
+    if err != nil {
+        return err
+    }
+    s.reportStoredEntry(ctx, entry, project)
+    entry.Conflicts = conflicts
+    return nil
 }
 
-func (s *TimeEntryService) UpdateTimeEntry(ctx context.Context, id string, update bson.M) error {
+// UpdateTimeEntry applies the update and returns the entries the updated
+// period overlaps when the owner's overlap policy only warns about them.
+func (s *TimeEntryService) UpdateTimeEntry(ctx context.Context, id string, update bson.M) ([]models.TimeEntry, error) {
     var existing models.TimeEntry
     err := s.timeEntryCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$eq": nil}}).Decode(&existing)
     if err != nil {
-        return err
+        return nil, err
+    }
+    if err := s.checkTimesheetLock(ctx, &existing); err != nil {
+        return nil, err
     }
     if note, ok := update["note"].(string); ok {
         existing.Note = note
@@ -65,20 +94,50 @@ func (s *TimeEntryService) UpdateTimeEntry(ctx context.Context, id string, updat
     if period, ok := update["period"].(models.TimePeriod); ok {
         existing.Period = period
     }
-    if existing.Reported != nil && existing.Reported.Done && existing.Reported.Integration == "jira" && existing.Reported.ExternalID != "" {
+    if started, ok := update["period.started"].(time.Time); ok {
+        existing.Period.Started = started
+    }
+    if ended, ok := update["period.ended"].(time.Time); ok {
+        existing.Period.Ended = ended
+    }
+    if duration, ok := update["period.duration"].(int); ok {
+        existing.Period.Duration = duration
+    }
+    // The entry must not be moved into a locked week or project either.
+    moved := existing
+    if projectID, ok := update["project_id"].(string); ok {
+        moved.ProjectID = projectID
+    }
+    if err := s.checkTimesheetLock(ctx, &moved); err != nil {
+        return nil, err
+    }
+    if existing.Running && !existing.Period.Ended.IsZero() {
+        update["running"] = false
+    }
+    var conflicts []models.TimeEntry
+    if _, ok := update["period.started"]; ok {
+        conflicts, err = s.checkOverlap(ctx, &existing, id)
+        if err != nil {
+            return nil, err
+        }
+    }
+    if existing.Reported != nil && existing.Reported.Done && existing.Reported.ExternalID != "" {
         project, err := s.projectService.GetProjectByID(ctx, existing.ProjectID, existing.OwnerID)
-        if err == nil && project.Integration.Type == "jira" {
-            _, err := s.atlassianService.UpdateTimeEntryInJira(&existing, project.Integration.ExternalID, existing.Reported.ExternalID)
+        if err == nil && project.Integration.Type == existing.Reported.Integration {
+            err := s.syncTimeEntry(&existing, SyncOperationUpdate, project.Integration.ExternalID)
             if err == nil {
-                now := time.Now()
-                existing.Reported.UpdatedAt = &now
                 update["reported"] = existing.Reported
+            } else {
+                s.enqueueSync(ctx, &existing, SyncOperationUpdate, project.Integration.ExternalID, err)
             }
         }
     }
     update["updated_at"] = time.Now()
     _, err = s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
-    return err
+    if err != nil {
+        return nil, err
+    }
+    return conflicts, nil
 }
 
 func (s *TimeEntryService) DeleteTimeEntry(ctx context.Context, id string) error {
@@ -87,24 +146,346 @@ func (s *TimeEntryService) DeleteTimeEntry(ctx context.Context, id string) error
     if err != nil {
         return err
     }
-    if existing.Reported != nil && existing.Reported.Done && existing.Reported.Integration == "jira" && existing.Reported.ExternalID != "" {
+    if err := s.checkTimesheetLock(ctx, &existing); err != nil {
+        return err
+    }
+    if existing.Reported != nil && existing.Reported.Done && existing.Reported.ExternalID != "" {
         project, err := s.projectService.GetProjectByID(ctx, existing.ProjectID, existing.OwnerID)
-        if err == nil && project.Integration.Type == "jira" {
-            err := s.atlassianService.RemoveTimeEntryFromJira(existing.OwnerID, project.Integration.ExternalID, existing.Reported.ExternalID)
+        if err == nil && project.Integration.Type == existing.Reported.Integration {
+            err := s.syncTimeEntry(&existing, SyncOperationDelete, project.Integration.ExternalID)
             if err == nil {
-                now := time.Now()
-                existing.Reported.UpdatedAt = &now
                 _, _ = s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"reported": existing.Reported}})
+            } else {
+                s.enqueueSync(ctx, &existing, SyncOperationDelete, project.Integration.ExternalID, err)
             }
         }
     }
     now := time.Now()
-    _, err = s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"deleted_at": now}})
+    _, err = s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"deleted_at": now, "running": false}})
+    return err
+}
+
+// reportToIntegration pushes a finished entry to the integration linked to its
+// project and records the result on entry.Reported. Failed attempts are queued
+// for the sync worker.
+func (s *TimeEntryService) reportToIntegration(ctx context.Context, entry *models.TimeEntry, project *models.Project) {
+    if _, ok := s.integrations.Get(project.Integration.Type); !ok {
+        return
+    }
+    entry.Reported = &models.ReportStatus{
+        Done:        false,
+        Integration: project.Integration.Type,
+    }
+    err := s.syncTimeEntry(entry, SyncOperationCreate, project.Integration.ExternalID)
+    if err != nil {
+        s.enqueueSync(ctx, entry, SyncOperationCreate, project.Integration.ExternalID, err)
+    }
+}
+
+// reportStoredEntry reports an entry that is already stored and saves the
+// report status on it. Reporting after the insert means a worklog is never
+// created for an entry that failed to be stored.
+func (s *TimeEntryService) reportStoredEntry(ctx context.Context, entry *models.TimeEntry, project *models.Project) {
+    s.reportToIntegration(ctx, entry, project)
+    if entry.Reported == nil {
+        return
+    }
+    _, err := s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": entry.ID}, bson.M{"$set": bson.M{"reported": entry.Reported}})
+    if err != nil {
+        log.Printf("Error saving report status of time entry %s: %v", entry.ID, err)
+    }
+}
+
+// syncTimeEntry performs a single worklog operation against the integration in
+// entry.Reported and updates entry.Reported accordingly. It does not persist
+// the entry.
+func (s *TimeEntryService) syncTimeEntry(entry *models.TimeEntry, operation string, issueID string) error {
+    if entry.Reported == nil {
+        return errors.New("time entry has no integration to sync with")
+    }
+    provider, ok := s.integrations.Get(entry.Reported.Integration)
+    if !ok {
+        return fmt.Errorf("%w: %s", ErrUnknownIntegration, entry.Reported.Integration)
+    }
+    now := time.Now()
+    switch operation {
+    case SyncOperationCreate:
+        worklogId, err := provider.CreateWorklog(entry, issueID)
+        if err != nil {
+            return err
+        }
+        entry.Reported = &models.ReportStatus{
+            Done:        true,
+            Integration: provider.Type(),
+            ExternalID:  worklogId,
+            ReportedAt:  &entry.Period.Started,
+            UpdatedAt:   &now,
+        }
+    case SyncOperationUpdate:
+        worklogId, err := provider.UpdateWorklog(entry, issueID, entry.Reported.ExternalID)
+        if err != nil {
+            return err
+        }
+        if worklogId != "" {
+            entry.Reported.ExternalID = worklogId
+        }
+        entry.Reported.UpdatedAt = &now
+    case SyncOperationDelete:
+        if err := provider.DeleteWorklog(entry.OwnerID, issueID, entry.Reported.ExternalID); err != nil {
+            return err
+        }
+        entry.Reported.UpdatedAt = &now
+    default:
+        return fmt.Errorf("unknown sync operation: %s", operation)
+    }
+    return nil
+}
+
+func (s *TimeEntryService) enqueueSync(ctx context.Context, entry *models.TimeEntry, operation string, issueID string, cause error) {
+    log.Printf("%s %s failed for time entry %s, queueing retry: %v", entry.Reported.Integration, operation, entry.ID, cause)
+    job := &models.SyncJob{
+        OwnerID:     entry.OwnerID,
+        TimeEntryID: entry.ID,
+        Integration: entry.Reported.Integration,
+        IssueID:     issueID,
+        Operation:   operation,
+    }
+    if err := s.syncJobService.Enqueue(ctx, job, cause); err != nil {
+        log.Printf("Error queueing %s %s for time entry %s: %v", entry.Reported.Integration, operation, entry.ID, err)
+    }
+}
+
+// ProcessSyncJob retries a queued worklog operation using the current state of
+// the time entry. Jobs made obsolete by later changes succeed without a request.
+func (s *TimeEntryService) ProcessSyncJob(ctx context.Context, job *models.SyncJob) error {
+    var entry models.TimeEntry
+    err := s.timeEntryCollection.FindOne(ctx, bson.M{"_id": job.TimeEntryID}).Decode(&entry)
+    if err != nil {
+        if errors.Is(err, mongo.ErrNoDocuments) {
+            return nil
+        }
+        return err
+    }
+
+    reported := entry.Reported != nil && entry.Reported.Done && entry.Reported.ExternalID != ""
+    switch job.Operation {
+    case SyncOperationCreate:
+        if entry.DeletedAt != nil || reported {
+            return nil
+        }
+        entry.Reported = &models.ReportStatus{Integration: job.Integration}
+    case SyncOperationUpdate:
+        if entry.DeletedAt != nil || !reported {
+            return nil
+        }
+    case SyncOperationDelete:
+        if !reported {
+            return nil
+        }
+    }
+
+    if err := s.syncTimeEntry(&entry, job.Operation, job.IssueID); err != nil {
+        return err
+    }
+    _, err = s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": entry.ID}, bson.M{"$set": bson.M{"reported": entry.Reported}})
+    return err
+}
+
+// ReportTimeEntries reports the given entries of the user to the integration
+// linked to their project. Entries that are already reported are left as is.
+func (s *TimeEntryService) ReportTimeEntries(ctx context.Context, ownerID string, ids []string) []models.TimeEntryReportResult {
+    results := make([]models.TimeEntryReportResult, 0, len(ids))
+    for _, id := range ids {
+        result := models.TimeEntryReportResult{ID: id}
+        reported, err := s.reportTimeEntry(ctx, ownerID, id)
+        if err != nil {
+            result.Error = err.Error()
+        }
+        result.Reported = reported
+        results = append(results, result)
+    }
+    return results
+}
+
+func (s *TimeEntryService) reportTimeEntry(ctx context.Context, ownerID string, id string) (*models.ReportStatus, error) {
+    filter := bson.M{"_id": id, "owner_id": ownerID, "deleted_at": bson.M{"$eq": nil}}
+    var entry models.TimeEntry
+    if err := s.timeEntryCollection.FindOne(ctx, filter).Decode(&entry); err != nil {
+        if errors.Is(err, mongo.ErrNoDocuments) {
+            return nil, errors.New("time entry not found")
+        }
+        return nil, err
+    }
+    if entry.Period.Ended.IsZero() {
+        return nil, errors.New("time entry is still running")
+    }
+    if entry.Reported != nil && entry.Reported.Done {
+        return entry.Reported, nil
+    }
+
+    project, err := s.projectService.GetProjectByID(ctx, entry.ProjectID, ownerID)
+    if err != nil {
+        return nil, errors.New("project not found")
+    }
+    if _, ok := s.integrations.Get(project.Integration.Type); !ok {
+        return nil, errors.New("project is not linked to an integration")
+    }
+
+    entry.Reported = &models.ReportStatus{Integration: project.Integration.Type}
+    if err := s.syncTimeEntry(&entry, SyncOperationCreate, project.Integration.ExternalID); err != nil {
+        return nil, err
+    }
+    _, err = s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": entry.ID}, bson.M{"$set": bson.M{"reported": entry.Reported}})
+    if err != nil {
+        return nil, err
+    }
+    return entry.Reported, nil
+}
+
+// GetRunningTimeEntry returns the open-ended entry of the user, or
+// mongo.ErrNoDocuments when no timer is running.
+func (s *TimeEntryService) GetRunningTimeEntry(ctx context.Context, ownerID string) (*models.TimeEntry, error) {
+    filter := bson.M{"owner_id": ownerID, "deleted_at": bson.M{"$eq": nil}, "period.ended": bson.M{"$exists": false}}
+    var entry models.TimeEntry
+    if err := s.timeEntryCollection.FindOne(ctx, filter).Decode(&entry); err != nil {
+        return nil, err
+    }
+    return &entry, nil
+}
+
+func (s *TimeEntryService) StartTimer(ctx context.Context, entry *models.TimeEntry) error {
+    running, err := s.GetRunningTimeEntry(ctx, entry.OwnerID)
+    if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+        return err
+    }
+    if running != nil {
+        return ErrTimerAlreadyRunning
+    }
+    entry.ID = uuid.New().String()
+    entry.Period = models.TimePeriod{Started: time.Now()}
+    if err := s.checkTimesheetLock(ctx, entry); err != nil {
+        return err
+    }
+    entry.Running = true
+    entry.CreatedAt = time.Now()
+    entry.UpdatedAt = time.Now()
+    entry.DeletedAt = nil
+    _, err = s.timeEntryCollection.InsertOne(ctx, entry)
+    // The unique index catches a timer started by a concurrent request.
+    if mongo.IsDuplicateKeyError(err) {
+        return ErrTimerAlreadyRunning
+    }
     return err
 }
 
-func (s *TimeEntryService) GetTimeEntries(ctx context.Context, ownerID string, from, to *time.Time, skip, limit int64) ([]models.TimeEntry, error) {
-    filter := bson.M{"owner_id": ownerID, "deleted_at": bson.M{"$eq": nil}}
+// StopTimer closes the running entry of the user and reports it to the
+// integration of its project. The note is only replaced when one is given.
+// Like a created entry, the closed period is checked against the overlap
+// policy of the user.
+func (s *TimeEntryService) StopTimer(ctx context.Context, ownerID string, note *string) (*models.TimeEntry, error) {
+    entry, err := s.GetRunningTimeEntry(ctx, ownerID)
+    if err != nil {
+        if errors.Is(err, mongo.ErrNoDocuments) {
+            return nil, ErrNoTimerRunning
+        }
+        return nil, err
+    }
+    if err := s.checkTimesheetLock(ctx, entry); err != nil {
+        return nil, err
+    }
+    if note != nil {
+        entry.Note = *note
+    }
+    entry.Period.Ended = time.Now()
+    entry.Period.Duration = int(entry.Period.Ended.Sub(entry.Period.Started).Seconds())
+    conflicts, err := s.checkOverlap(ctx, entry, entry.ID)
+    if err != nil {
+        return nil, err
+    }
+
+    entry.Running = false
+    entry.UpdatedAt = time.Now()
+    update := bson.M{
+        "period":     entry.Period,
+        "note":       entry.Note,
+        "running":    false,
+        "updated_at": entry.UpdatedAt,
+    }
+    _, err = s.timeEntryCollection.UpdateOne(ctx, bson.M{"_id": entry.ID}, bson.M{"$set": update})
+    if err != nil {
+        return nil, err
+    }
+    entry.Conflicts = conflicts
+
+    project, err := s.projectService.GetProjectByID(ctx, entry.ProjectID, entry.OwnerID)
+    if err != nil {
+        log.Println("Error getting project:", err)
+    } else {
+        s.reportStoredEntry(ctx, entry, project)
+    }
+    return entry, nil
+}
+
+func (s *TimeEntryService) GetTimeEntryByID(ctx context.Context, id string, ownerID string) (*models.TimeEntry, error) {
+    filter := bson.M{"_id": id, "owner_id": ownerID, "deleted_at": bson.M{"$eq": nil}}
+    var entry models.TimeEntry
+    if err := s.timeEntryCollection.FindOne(ctx, filter).Decode(&entry); err != nil {
+        return nil, err
+    }
+    return &entry, nil
+}
+
+// FindOverlappingEntries returns the finished entries of the user that share at
+// least one second with the given period. excludeID skips the entry being edited.
+func (s *TimeEntryService) FindOverlappingEntries(ctx context.Context, ownerID string, start, end time.Time, excludeID string) ([]models.TimeEntry, error) {
+    filter := bson.M{
+        "owner_id":       ownerID,
+        "deleted_at":     bson.M{"$eq": nil},
+        "period.started": bson.M{"$lt": end},
+        "period.ended":   bson.M{"$gt": start},
+    }
+    if excludeID != "" {
+        filter["_id"] = bson.M{"$ne": excludeID}
+    }
+    cursor, err := s.timeEntryCollection.Find(ctx, filter)
+    if err != nil {
+        return nil, err
+    }
+    entries := []models.TimeEntry{}
+    if err := cursor.All(ctx, &entries); err != nil {
+        return nil, err
+    }
+    return entries, nil
+}
+
+// checkOverlap applies the owner's overlap policy to the period of the entry.
+// It returns an *OverlapError when the entry must be rejected, and the
+// conflicting entries when the policy only warns about them.
+func (s *TimeEntryService) checkOverlap(ctx context.Context, entry *models.TimeEntry, excludeID string) ([]models.TimeEntry, error) {
+    if entry.Period.Ended.IsZero() {
+        return nil, nil
+    }
+    policy, err := s.userService.GetOverlapPolicy(ctx, entry.OwnerID)
+    if err != nil {
+        return nil, err
+    }
+    if policy == models.OverlapPolicyAllow {
+        return nil, nil
+    }
+    conflicts, err := s.FindOverlappingEntries(ctx, entry.OwnerID, entry.Period.Started, entry.Period.Ended, excludeID)
+    if err != nil || len(conflicts) == 0 {
+        return nil, err
+    }
+    if policy == models.OverlapPolicyWarn {
+        return conflicts, nil
+    }
+    return nil, &OverlapError{Conflicts: conflicts}
+}
+
+// timeEntryFilter matches the finished, non-deleted entries of the user that
+// started within the optional date range.
+func timeEntryFilter(ownerID string, from, to *time.Time) bson.M {
+    filter := bson.M{"owner_id": ownerID, "deleted_at": bson.M{"$eq": nil}, "period.ended": bson.M{"$exists": true}}
     if from != nil || to != nil {
         dateRange := bson.M{}
         if from != nil {
@@ -115,21 +496,53 @@ func (s *TimeEntryService) GetTimeEntries(ctx context.Context, ownerID string, f
         }
         filter["period.started"] = dateRange
     }
-    pipeline := bson.A{
-        bson.D{{Key: "$match", Value: filter}},
-        bson.D{{Key: "$skip", Value: skip}},
-        bson.D{{Key: "$limit", Value: limit}},
-        bson.D{{Key: "$sort", Value: bson.D{{Key: "period.started", Value: -1}}}},
+    return filter
+}
+
+// GetTimeEntries returns a page of the owner's entries that match the filter,
+// newest first, with the total number of matching entries. The page starts
+// after the cursor of the previous page if one is given, and skip entries after
+// that.
+func (s *TimeEntryService) GetTimeEntries(ctx context.Context, ownerID string, f TimeEntryFilter, cursor string, skip, limit int64) (*models.TimeEntryPage, error) {
+    filter, err := s.listFilter(ctx, ownerID, f)
+    if err != nil {
+        return nil, err
     }
-    cursor, err := s.timeEntryCollection.Aggregate(ctx, pipeline)
+    total, err := s.timeEntryCollection.CountDocuments(ctx, filter)
     if err != nil {
         return nil, err
     }
-    var entries []models.TimeEntry
-    if err := cursor.All(ctx, &entries); err != nil {
+    if cursor != "" {
+        after, err := decodeCursor(cursor)
+        if err != nil {
+            return nil, err
+        }
+        started, err := time.Parse(time.RFC3339Nano, after.Key)
+        if err != nil {
+            return nil, ErrInvalidCursor
+        }
+        afterCursor(filter, "period.started", started, after.ID, true)
+    }
+
+    // One more than the limit is fetched to tell whether there is a next page.
+    opts := options.Find().
+        SetSort(bson.D{{Key: "period.started", Value: -1}, {Key: "_id", Value: -1}}).
+        SetSkip(skip).
+        SetLimit(limit + 1)
+    found, err := s.timeEntryCollection.Find(ctx, filter, opts)
+    if err != nil {
         return nil, err
     }
-    return entries, nil
+    page := &models.TimeEntryPage{Entries: []models.TimeEntry{}, Total: total}
+    if err := found.All(ctx, &page.Entries); err != nil {
+        return nil, err
+    }
+    if int64(len(page.Entries)) > limit {
+        page.Entries = page.Entries[:limit]
+        last := page.Entries[limit-1]
+        page.NextCursor = encodeCursor(last.Period.Started.Format(time.RFC3339Nano), last.ID)
+    }
+    return page, nil
 }
 
 func (s *TimeEntryService) GetTimeEntryStatistics(
@@ -141,17 +554,42 @@ func (s *TimeEntryService) GetTimeEntryStatistics(
     if format != "d" && format != "w" && format != "m" {
         return nil, fmt.Errorf("invalid format: %s, must be one of 'd', 'w', or 'm'", format)
     }
-    filter := bson.M{"owner_id": ownerID, "deleted_at": bson.M{"$eq": nil}}
-    if from != nil || to != nil {
-        dateRange := bson.M{}
-        if from != nil {
-            dateRange["$gte"] = *from
-        }
-        if to != nil {
-            dateRange["$lte"] = *to
-        }
-        filter["period.started"] = dateRange
+    loc, err := s.userService.GetLocation(ctx, ownerID)
+    if err != nil {
+        return nil, err
+    }
+    return s.aggregateStatistics(ctx, timeEntryFilter(ownerID, from, to), loc, format)
+}
+
+// GetTeamStatistics returns the statistics of the time the members logged
+// against the projects of the workspace, grouped in the timezone of the viewer.
+// Time on personal projects of the members is left out.
+func (s *TimeEntryService) GetTeamStatistics(
+    ctx context.Context,
+    viewerID string,
+    workspaceID string,
+    memberIDs []string,
+    from, to *time.Time,
+    format string,
+) (*models.TimeEntryStatistics, error) {
+    if format != "d" && format != "w" && format != "m" {
+        return nil, fmt.Errorf("invalid format: %s, must be one of 'd', 'w', or 'm'", format)
     }
+    loc, err := s.userService.GetLocation(ctx, viewerID)
+    if err != nil {
+        return nil, err
+    }
+    projectIDs, err := s.projectService.GetWorkspaceProjectIDs(ctx, workspaceID)
+    if err != nil {
+        return nil, err
+    }
+    filter := timeEntryFilter("", from, to)
+    filter["owner_id"] = bson.M{"$in": memberIDs}
+    filter["project_id"] = bson.M{"$in": projectIDs}
+    return s.aggregateStatistics(ctx, filter, loc, format)
+}
+
+func (s *TimeEntryService) aggregateStatistics(ctx context.Context, filter bson.M, loc *time.Location, format string) (*models.TimeEntryStatistics, error) {
     var dateFormat string
     switch format {
     case "d":
@@ -172,7 +610,7 @@ func (s *TimeEntryService) GetTimeEntryStatistics(
                                 "$dateToString": bson.M{
                                     "format":   dateFormat,
                                     "date":     "$period.started",
-                                    "timezone": "UTC",
+                                    "timezone": loc.String(),
                                 },
                             },
                         },
@@ -197,6 +635,19 @@ func (s *TimeEntryService) GetTimeEntryStatistics(
                         "_id":        0,
                     }}},
                 },
+                "perTag": bson.A{
+                    bson.D{{Key: "$unwind", Value: "$tags"}},
+                    bson.D{{Key: "$group", Value: bson.M{
+                        "_id":        "$tags",
+                        "total_time": bson.M{"$sum": "$period.duration"},
+                    }}},
+                    bson.D{{Key: "$sort", Value: bson.D{{Key: "total_time", Value: -1}, {Key: "_id", Value: 1}}}},
+                    bson.D{{Key: "$project", Value: bson.M{
+                        "tag":        "$_id",
+                        "total_time": 1,
+                        "_id":        0,
+                    }}},
+                },
                 "totalTime": bson.A{
                     bson.D{{Key: "$group", Value: bson.M{
                         "_id":        nil,
@@ -217,6 +668,7 @@ func (s *TimeEntryService) GetTimeEntryStatistics(
     var results []struct {
         PerDate     []models.TimeEntryStatPerDate `bson:"perDate"`
         PerProject  []models.TimeEntryPerProject  `bson:"perProject"`
+        PerTag      []models.TimeEntryPerTag      `bson:"perTag"`
         TotalTimeAr []struct {
             TotalTime int64 `bson:"total_time"`
         } `bson:"totalTime"`
@@ -227,25 +679,28 @@ func (s *TimeEntryService) GetTimeEntryStatistics(
     if err := cursor.All(ctx, &results); err != nil {
         return nil, err
     }
-    if len(results) == 0 {
-        return &models.TimeEntryStatistics{
-            TotalEntries:      0,
-            TotalTime:         0,
-            Format:            format,
-            EntriesPerDate:    []models.TimeEntryStatPerDate{},
-            EntriesPerProject: []models.TimeEntryPerProject{},
-        }, nil
-    }
     stats := &models.TimeEntryStatistics{
+        TotalEntries:      0,
+        TotalTime:         0,
         Format:            format,
-        EntriesPerDate:    results[0].PerDate,
-        EntriesPerProject: results[0].PerProject,
+        Timezone:          loc.String(),
+        EntriesPerDate:    []models.TimeEntryStatPerDate{},
+        EntriesPerProject: []models.TimeEntryPerProject{},
+        EntriesPerTag:     []models.TimeEntryPerTag{},
     }
-    if len(results[0].TotalTimeAr) > 0 {
-        stats.TotalTime = results[0].TotalTimeAr[0].TotalTime
+    if len(results) > 0 {
+        stats.EntriesPerDate = results[0].PerDate
+        stats.EntriesPerProject = results[0].PerProject
+        stats.EntriesPerTag = results[0].PerTag
+        if len(results[0].TotalTimeAr) > 0 {
+            stats.TotalTime = results[0].TotalTimeAr[0].TotalTime
+        }
+        if len(results[0].MatchCountAr) > 0 {
+            stats.TotalEntries = results[0].MatchCountAr[0].Count
+        }
     }
-    if len(results[0].MatchCountAr) > 0 {
-        stats.TotalEntries = results[0].MatchCountAr[0].Count
+    if err := s.addBillable(ctx, filter, stats); err != nil {
+        return nil, err
     }
     return stats, nil
 }
@@ -0,0 +1,19 @@
+package services
+
+import "strings"
+
+// NormalizeTags trims and lower-cases the tags and drops empty and duplicate
+// ones, so "Meeting" and "meeting " count as the same tag.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrEntryLocked = errors.New("time entry is in a submitted or approved timesheet")
+	ErrInvalidWeek = errors.New("invalid week, must be an ISO week like 2026-W38")
+)
+
+// ParseISOWeek returns the start of the ISO week, such as "2026-W38", in loc
+// and the start of the week after. The format matches the "%G-W%V" grouping of
+// the statistics.
+func ParseISOWeek(week string, loc *time.Location) (time.Time, time.Time, error) {
+	var year, number int
+	if _, err := fmt.Sscanf(week, "%4d-W%2d", &year, &number); err != nil || len(week) != len("2006-W01") {
+		return time.Time{}, time.Time{}, ErrInvalidWeek
+	}
+	// January 4th is always in week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	start := monday.AddDate(0, 0, (number-1)*7)
+	if y, w := start.ISOWeek(); number < 1 || y != year || w != number {
+		return time.Time{}, time.Time{}, ErrInvalidWeek
+	}
+	return start, start.AddDate(0, 0, 7), nil
+}
+
+// SubmitTimesheet submits the time the user logged against the projects of the
+// workspace in the ISO week for approval. The entries are locked until the
+// timesheet is rejected.
+func (s *TimeEntryService) SubmitTimesheet(ctx context.Context, ownerID string, workspaceID string, week string) (*models.Timesheet, error) {
+	loc, err := s.userService.GetLocation(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := ParseISOWeek(week, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet := &models.Timesheet{
+		OwnerID:     ownerID,
+		WorkspaceID: workspaceID,
+		Week:        week,
+		Start:       start,
+		End:         end,
+	}
+	entries, err := s.GetTimesheetEntries(ctx, timesheet)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		timesheet.TotalTime += int64(entry.Period.Duration)
+	}
+	timesheet.TotalEntries = int64(len(entries))
+
+	if err := s.timesheetService.Submit(ctx, timesheet); err != nil {
+		return nil, err
+	}
+	return timesheet, nil
+}
+
+// GetTimesheetEntries returns the finished entries of the timesheet, the
+// earliest first.
+func (s *TimeEntryService) GetTimesheetEntries(ctx context.Context, timesheet *models.Timesheet) ([]models.TimeEntry, error) {
+	projectIDs, err := s.projectService.GetWorkspaceProjectIDs(ctx, timesheet.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{
+		"owner_id":       timesheet.OwnerID,
+		"project_id":     bson.M{"$in": projectIDs},
+		"deleted_at":     bson.M{"$eq": nil},
+		"period.ended":   bson.M{"$exists": true},
+		"period.started": bson.M{"$gte": timesheet.Start, "$lt": timesheet.End},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "period.started", Value: 1}})
+	cursor, err := s.timeEntryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	entries := []models.TimeEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// checkTimesheetLock returns ErrEntryLocked when the entry falls in a week whose
+// timesheet in the workspace of its project is submitted or approved. Entries
+// on personal projects are never locked.
+func (s *TimeEntryService) checkTimesheetLock(ctx context.Context, entry *models.TimeEntry) error {
+	project, err := s.projectService.GetProjectByID(ctx, entry.ProjectID, entry.OwnerID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.checkWeekLock(ctx, entry.OwnerID, project.WorkspaceID, entry.Period.Started)
+}
+
+// checkWeekLock returns ErrEntryLocked when the owner's timesheet in the
+// workspace for the week of started is submitted or approved. An empty
+// workspaceID stands for personal projects, which are never locked.
+func (s *TimeEntryService) checkWeekLock(ctx context.Context, ownerID string, workspaceID string, started time.Time) error {
+	if workspaceID == "" {
+		return nil
+	}
+	locked, err := s.timesheetService.IsLocked(ctx, ownerID, workspaceID, started)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrEntryLocked
+	}
+	return nil
+}
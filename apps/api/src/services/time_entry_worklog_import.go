@@ -0,0 +1,230 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrImportUnsupported     = errors.New("integration does not support importing worklogs")
+	ErrImportProjectConflict = errors.New("project named after the issue is linked to another issue")
+)
+
+// ImportWorklogs creates time entries for the worklogs the user logged in the
+// integration within the range. The entries are marked as reported. Worklogs
+// that already belong to an entry, even a deleted one, are skipped, so running
+// the import again does not create duplicates. Issues without a project in the
+// workspace, or among the personal projects when workspaceID is empty, get one
+// named after the issue key. Like a bulk import, worklogs are checked against
+// the overlap policy of the user and overlapping ones are skipped when it
+// rejects them.
+func (s *TimeEntryService) ImportWorklogs(ctx context.Context, ownerID string, workspaceID string, integration string, from, to time.Time, dryRun bool) (*models.WorklogImportResult, error) {
+	provider, ok := s.integrations.Get(integration)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownIntegration, integration)
+	}
+	fetcher, ok := provider.(WorklogFetcher)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrImportUnsupported, integration)
+	}
+
+	worklogs, err := fetcher.FetchWorklogs(ownerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(worklogs, func(i, j int) bool {
+		return worklogs[i].Started.Before(worklogs[j].Started)
+	})
+	result := &models.WorklogImportResult{
+		DryRun:          dryRun,
+		Worklogs:        len(worklogs),
+		ProjectsCreated: []string{},
+		Entries:         []models.TimeEntry{},
+	}
+
+	ids := make([]string, 0, len(worklogs))
+	for _, worklog := range worklogs {
+		ids = append(ids, worklog.ID)
+	}
+	imported, err := s.importedWorklogIDs(ctx, ownerID, integration, ids)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.userService.GetOverlapPolicy(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make(map[string]*models.Project)
+	now := time.Now()
+	for i := range worklogs {
+		worklog := &worklogs[i]
+		if imported[worklog.ID] {
+			result.Skipped++
+			continue
+		}
+		imported[worklog.ID] = true
+
+		// Projects are looked up and created in the workspace, so that is
+		// where the week may be locked.
+		if err := s.checkWeekLock(ctx, ownerID, workspaceID, worklog.Started); err != nil {
+			if !errors.Is(err, ErrEntryLocked) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, worklogError(worklog, err))
+			continue
+		}
+
+		started := worklog.Started
+		ended := started.Add(time.Duration(worklog.Duration) * time.Second)
+		var conflicts []models.TimeEntry
+		if policy != models.OverlapPolicyAllow {
+			conflicts, err = s.importOverlaps(ctx, ownerID, started, ended, result.Entries)
+			if err != nil {
+				return nil, err
+			}
+			if len(conflicts) > 0 && policy == models.OverlapPolicyReject {
+				result.Errors = append(result.Errors, worklogError(worklog, &OverlapError{Conflicts: conflicts}))
+				continue
+			}
+		}
+
+		project, err := s.importProject(ctx, ownerID, workspaceID, integration, worklog, projects, result, dryRun)
+		if errors.Is(err, ErrImportProjectConflict) {
+			result.Errors = append(result.Errors, worklogError(worklog, err))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Entries = append(result.Entries, models.TimeEntry{
+			ID:        uuid.New().String(),
+			ProjectID: project.ID,
+			OwnerID:   ownerID,
+			Period: models.TimePeriod{
+				Started:  started,
+				Ended:    ended,
+				Duration: worklog.Duration,
+			},
+			Note:     worklog.Comment,
+			Billable: project.Billable,
+			Reported: &models.ReportStatus{
+				Done:        true,
+				Integration: integration,
+				ExternalID:  worklog.ID,
+				ReportedAt:  &started,
+				UpdatedAt:   &now,
+				CheckedAt:   &now,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+			Conflicts: conflicts,
+		})
+	}
+	result.Imported = len(result.Entries)
+
+	if dryRun || len(result.Entries) == 0 {
+		return result, nil
+	}
+	docs := make([]interface{}, 0, len(result.Entries))
+	for i := range result.Entries {
+		docs = append(docs, &result.Entries[i])
+	}
+	if _, err := s.timeEntryCollection.InsertMany(ctx, docs); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// worklogError describes why the worklog was not imported.
+func worklogError(worklog *models.Worklog, err error) string {
+	return fmt.Sprintf("%s %s: %v", worklog.IssueKey, worklog.Started.Format(time.RFC3339), err)
+}
+
+// importOverlaps returns the entries of the user, and the entries imported
+// before in the same run, that overlap the period.
+func (s *TimeEntryService) importOverlaps(ctx context.Context, ownerID string, started, ended time.Time, imported []models.TimeEntry) ([]models.TimeEntry, error) {
+	conflicts, err := s.FindOverlappingEntries(ctx, ownerID, started, ended, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range imported {
+		if entry.Period.Started.Before(ended) && entry.Period.Ended.After(started) {
+			entry.Conflicts = nil
+			conflicts = append(conflicts, entry)
+		}
+	}
+	return conflicts, nil
+}
+
+// importedWorklogIDs returns which of the worklogs belong to an entry of the
+// user, including deleted entries.
+func (s *TimeEntryService) importedWorklogIDs(ctx context.Context, ownerID string, integration string, worklogIDs []string) (map[string]bool, error) {
+	imported := make(map[string]bool)
+	if len(worklogIDs) == 0 {
+		return imported, nil
+	}
+	filter := bson.M{
+		"owner_id":             ownerID,
+		"reported.integration": integration,
+		"reported.external_id": bson.M{"$in": worklogIDs},
+	}
+	cursor, err := s.timeEntryCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var entries []models.TimeEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		imported[entry.Reported.ExternalID] = true
+	}
+	return imported, nil
+}
+
+// importProject returns the project for the issue of the worklog. An unlinked
+// project named after the issue key is linked to it, otherwise a new project is
+// created. A project with that name that is linked to another issue is
+// reported as ErrImportProjectConflict rather than duplicated. Nothing is
+// stored on a dry run.
+func (s *TimeEntryService) importProject(ctx context.Context, ownerID string, workspaceID string, integration string, worklog *models.Worklog, projects map[string]*models.Project, result *models.WorklogImportResult, dryRun bool) (*models.Project, error) {
+	if project, ok := projects[worklog.IssueID]; ok {
+		return project, nil
+	}
+
+	info := models.IntegrationInfo{Type: integration, Key: worklog.IssueKey, ExternalID: worklog.IssueID}
+	project, err := s.projectService.GetProjectByIssue(ctx, ownerID, workspaceID, integration, worklog.IssueID, worklog.IssueKey)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		project, err = s.projectService.GetProjectByName(ctx, ownerID, workspaceID, worklog.IssueKey)
+		switch {
+		case err == nil && project.Integration.Type == "":
+			project.Integration = info
+			if !dryRun {
+				err = s.projectService.UpdateProject(ctx, project.ID, bson.M{"integration": info})
+			}
+		case err == nil:
+			return nil, fmt.Errorf("%w: %s is linked to %s %s", ErrImportProjectConflict, project.Name, project.Integration.Type, project.Integration.Key)
+		case errors.Is(err, mongo.ErrNoDocuments):
+			project = &models.Project{Name: worklog.IssueKey, OwnerID: ownerID, WorkspaceID: workspaceID, Integration: info}
+			result.ProjectsCreated = append(result.ProjectsCreated, project.Name)
+			err = nil
+			if !dryRun {
+				err = s.projectService.CreateLinkedProject(ctx, project)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	projects[worklog.IssueID] = project
+	return project, nil
+}
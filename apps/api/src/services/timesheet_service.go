@@ -0,0 +1,153 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrTimesheetLocked       = errors.New("the timesheet of the week is already submitted or approved")
+	ErrTimesheetNotSubmitted = errors.New("only submitted timesheets can be reviewed")
+	ErrOwnTimesheet          = errors.New("timesheets cannot be reviewed by their owner")
+)
+
+type TimesheetService struct {
+	timesheetCollection *mongo.Collection
+}
+
+func NewTimesheetService(db *mongo.Database) *TimesheetService {
+	return &TimesheetService{timesheetCollection: db.Collection("timesheets")}
+}
+
+// EnsureIndexes creates the unique index that allows one timesheet per user,
+// workspace and week. Creating an index that already exists does nothing.
+func (s *TimesheetService) EnsureIndexes(ctx context.Context) error {
+	_, err := s.timesheetCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "workspace_id", Value: 1}, {Key: "week", Value: 1}},
+		Options: options.Index().
+			SetName("owner_id_workspace_id_week").
+			SetUnique(true),
+	})
+	return err
+}
+
+// Submit stores the timesheet as submitted. A rejected timesheet of the same
+// week is replaced, while a submitted or approved one returns
+// ErrTimesheetLocked.
+func (s *TimesheetService) Submit(ctx context.Context, timesheet *models.Timesheet) error {
+	now := time.Now()
+	timesheet.Status = models.TimesheetSubmitted
+	timesheet.SubmittedAt = now
+	timesheet.UpdatedAt = now
+
+	filter := bson.M{"owner_id": timesheet.OwnerID, "workspace_id": timesheet.WorkspaceID, "week": timesheet.Week}
+	var existing models.Timesheet
+	err := s.timesheetCollection.FindOne(ctx, filter).Decode(&existing)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		timesheet.ID = uuid.New().String()
+		timesheet.CreatedAt = now
+		_, err = s.timesheetCollection.InsertOne(ctx, timesheet)
+		// The unique index catches a timesheet submitted concurrently.
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrTimesheetLocked
+		}
+		return err
+	case err != nil:
+		return err
+	case existing.Status != models.TimesheetRejected:
+		return ErrTimesheetLocked
+	}
+
+	timesheet.ID = existing.ID
+	timesheet.CreatedAt = existing.CreatedAt
+	res, err := s.timesheetCollection.ReplaceOne(ctx, bson.M{"_id": existing.ID, "status": models.TimesheetRejected}, timesheet)
+	if err != nil {
+		return err
+	}
+	// The rejected timesheet was submitted again in the meantime.
+	if res.MatchedCount == 0 {
+		return ErrTimesheetLocked
+	}
+	return nil
+}
+
+func (s *TimesheetService) GetTimesheet(ctx context.Context, id string) (*models.Timesheet, error) {
+	var timesheet models.Timesheet
+	if err := s.timesheetCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&timesheet); err != nil {
+		return nil, err
+	}
+	return &timesheet, nil
+}
+
+// GetTimesheets returns the timesheets matching the non-empty arguments, the
+// latest week first.
+func (s *TimesheetService) GetTimesheets(ctx context.Context, ownerID string, workspaceID string, status string) ([]models.Timesheet, error) {
+	filter := bson.M{}
+	if ownerID != "" {
+		filter["owner_id"] = ownerID
+	}
+	if workspaceID != "" {
+		filter["workspace_id"] = workspaceID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: -1}, {Key: "owner_id", Value: 1}})
+	cursor, err := s.timesheetCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	timesheets := []models.Timesheet{}
+	if err := cursor.All(ctx, &timesheets); err != nil {
+		return nil, err
+	}
+	return timesheets, nil
+}
+
+// Review approves or rejects a submitted timesheet.
+func (s *TimesheetService) Review(ctx context.Context, timesheet *models.Timesheet, reviewerID string, status string, comment string) (*models.Timesheet, error) {
+	if timesheet.OwnerID == reviewerID {
+		return nil, ErrOwnTimesheet
+	}
+	now := time.Now()
+	filter := bson.M{"_id": timesheet.ID, "status": models.TimesheetSubmitted}
+	update := bson.M{"$set": bson.M{
+		"status":      status,
+		"reviewed_by": reviewerID,
+		"reviewed_at": now,
+		"comment":     comment,
+		"updated_at":  now,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Timesheet
+	err := s.timesheetCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrTimesheetNotSubmitted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// IsLocked reports whether a submitted or approved timesheet of the owner in the
+// workspace covers t.
+func (s *TimesheetService) IsLocked(ctx context.Context, ownerID string, workspaceID string, t time.Time) (bool, error) {
+	filter := bson.M{
+		"owner_id":     ownerID,
+		"workspace_id": workspaceID,
+		"status":       bson.M{"$in": bson.A{models.TimesheetSubmitted, models.TimesheetApproved}},
+		"start":        bson.M{"$lte": t},
+		"end":          bson.M{"$gt": t},
+	}
+	count, err := s.timesheetCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return count > 0, err
+}
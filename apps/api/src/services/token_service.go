@@ -1,27 +1,150 @@
 package services
 
 import (
+	"TimeTrack-shared/models"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	// accessTokenLifetime is short, since access tokens cannot be revoked and
+	// clients refresh them with their session.
+	accessTokenLifetime = 15 * time.Minute
+	// sessionLifetime is how long a session stays valid without being used.
+	sessionLifetime = 30 * 24 * time.Hour
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
 type TokenService struct {
-	jwtSecret string
+	jwtSecret         string
+	sessionCollection *mongo.Collection
 }
 
 func NewTokenService(db *mongo.Database, jwtSecret string) *TokenService {
 	return &TokenService{
-		jwtSecret: jwtSecret,
+		jwtSecret:         jwtSecret,
+		sessionCollection: db.Collection("sessions"),
 	}
 }
 
-func (s *TokenService) GenerateAuthToken(userID, email string) (string, error) {
+// GenerateAuthToken returns an access token for the session and its expiry.
+func (s *TokenService) GenerateAuthToken(userID, email, sessionID string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(accessTokenLifetime)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"userId": userID,
 		"email":  email,
-		"exp":    time.Now().Add(time.Hour * 72).Unix(),
+		"sid":    sessionID,
+		"exp":    expiresAt.Unix(),
 	})
-	return token.SignedString([]byte(s.jwtSecret))
+	signed, err := token.SignedString([]byte(s.jwtSecret))
+	return signed, expiresAt, err
+}
+
+// CreateSession starts a session for a login and returns its tokens. The
+// refresh token is only returned here, the session stores its hash.
+func (s *TokenService) CreateSession(ctx context.Context, userID, email, deviceName string) (*models.AuthTokens, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	session := models.Session{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Email:      email,
+		TokenHash:  hashToken(refreshToken),
+		DeviceName: deviceName,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(sessionLifetime),
+	}
+	if _, err := s.sessionCollection.InsertOne(ctx, session); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := s.GenerateAuthToken(userID, email, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuthTokens{
+		Token:        token,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+		SessionID:    session.ID,
+	}, nil
+}
+
+// RefreshSession returns a new access token for the session of the refresh
+// token and extends the session.
+func (s *TokenService) RefreshSession(ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
+	now := time.Now()
+	filter := bson.M{
+		"token_hash": hashToken(refreshToken),
+		"revoked_at": bson.M{"$eq": nil},
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"last_used_at": now, "expires_at": now.Add(sessionLifetime)}}
+	var session models.Session
+	err := s.sessionCollection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	token, expiresAt, err := s.GenerateAuthToken(session.UserID, session.Email, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuthTokens{Token: token, ExpiresAt: expiresAt, SessionID: session.ID}, nil
+}
+
+// ListSessions returns the active sessions of the user, most recently used
+// first.
+func (s *TokenService) ListSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	filter := bson.M{"user_id": userID, "revoked_at": bson.M{"$eq": nil}, "expires_at": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.D{{Key: "last_used_at", Value: -1}})
+	cursor, err := s.sessionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []models.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends a session of the user, so its refresh token stops working.
+// Access tokens already issued stay valid until they expire.
+func (s *TokenService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	filter := bson.M{"_id": sessionID, "user_id": userID, "revoked_at": bson.M{"$eq": nil}}
+	res, err := s.sessionCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -11,17 +11,23 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 
+	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
 )
 
 var userProjection = bson.M{
-	"_id":                           1,
-	"email":                         1,
-	"firstName":                     1,
-	"lastName":                      1,
-	"createdAt":                     1,
-	"updatedAt":                     1,
-	"integration.atlassian.enabled": 1,
+	"_id":                                   1,
+	"email":                                 1,
+	"firstName":                             1,
+	"lastName":                              1,
+	"createdAt":                             1,
+	"updatedAt":                             1,
+	"integration.atlassian.enabled":         1,
+	"integration.atlassian.reauth_required": 1,
+	"integration.gitlab.enabled":            1,
+	"integration.gitlab.base_url":           1,
+	"overlap_policy":                        1,
+	"timezone":                              1,
 }
 
 type UserService struct {
@@ -83,11 +89,67 @@ func (s *UserService) GetUserByEmail(c *gin.Context, email string) (*models.User
 	return &user, nil
 }
 
+func (s *UserService) UpdateUser(c *gin.Context, userID string, input *dtos.UpdateUserInput) error {
+	update := bson.M{"updated_at": time.Now()}
+	if input.OverlapPolicy != nil {
+		update["overlap_policy"] = *input.OverlapPolicy
+	}
+	if input.Timezone != nil {
+		update["timezone"] = *input.Timezone
+	}
+	_, err := s.userCollection.UpdateOne(c, bson.M{"_id": userID}, bson.M{"$set": update})
+	return err
+}
+
+// GetOverlapPolicy returns the overlap policy of the user, defaulting to
+// models.OverlapPolicyReject.
+func (s *UserService) GetOverlapPolicy(ctx context.Context, userID string) (string, error) {
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"overlap_policy": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		return "", err
+	}
+	if user.OverlapPolicy == "" {
+		return models.OverlapPolicyReject, nil
+	}
+	return user.OverlapPolicy, nil
+}
+
+// GetLocation returns the timezone of the user, defaulting to UTC.
+func (s *UserService) GetLocation(ctx context.Context, userID string) (*time.Location, error) {
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"timezone": 1})
+	if err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		return nil, err
+	}
+	if user.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
 func (s *UserService) UpdateIntegration(c *gin.Context, userID string, integrationType string, integration models.UserIntegration) error {
 	_, err := s.userCollection.UpdateOne(context.TODO(), bson.M{"_id": userID}, bson.M{"$set": bson.M{"integration": integration}})
 	return err
 }
 
+func (s *UserService) UpdateAtlassianIntegration(userID string, integration models.AtlassianIntegration) error {
+	_, err := s.userCollection.UpdateOne(context.TODO(), bson.M{"_id": userID}, bson.M{"$set": bson.M{"integration.atlassian": integration}})
+	return err
+}
+
+// SetAtlassianSite caches the Jira cloud ID and account ID of the user on
+// their Atlassian integration.
+func (s *UserService) SetAtlassianSite(userID string, cloudID string, accountID string) error {
+	update := bson.M{"integration.atlassian.cloud_id": cloudID, "integration.atlassian.account_id": accountID}
+	_, err := s.userCollection.UpdateOne(context.TODO(), bson.M{"_id": userID}, bson.M{"$set": update})
+	return err
+}
+
 func (s *UserService) GetAtlassianIntegration(userID string) (*models.AtlassianIntegration, error) {
 	var user models.User
 	err := s.userCollection.FindOne(context.TODO(), bson.M{"_id": userID}).Decode(&user)
@@ -96,3 +158,36 @@ func (s *UserService) GetAtlassianIntegration(userID string) (*models.AtlassianI
 	}
 	return &user.Integration.Atlassian, nil
 }
+
+// GetAtlassianUserIDs returns the users with a working Atlassian integration.
+func (s *UserService) GetAtlassianUserIDs(ctx context.Context) ([]string, error) {
+	filter := bson.M{"integration.atlassian.enabled": true, "integration.atlassian.reauth_required": bson.M{"$ne": true}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := s.userCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
+
+func (s *UserService) UpdateGitLabIntegration(userID string, integration models.GitLabIntegration) error {
+	_, err := s.userCollection.UpdateOne(context.TODO(), bson.M{"_id": userID}, bson.M{"$set": bson.M{"integration.gitlab": integration}})
+	return err
+}
+
+func (s *UserService) GetGitLabIntegration(userID string) (*models.GitLabIntegration, error) {
+	var user models.User
+	err := s.userCollection.FindOne(context.TODO(), bson.M{"_id": userID}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user.Integration.GitLab, nil
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"TimeTrack-shared/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrAlreadyMember    = errors.New("user is already a member of the workspace")
+	ErrOwnerCannotLeave = errors.New("the owner cannot leave the workspace")
+	ErrOwnerRoleFixed   = errors.New("the role of the owner cannot be changed")
+)
+
+type WorkspaceService struct {
+	workspaceCollection *mongo.Collection
+}
+
+func NewWorkspaceService(db *mongo.Database) *WorkspaceService {
+	return &WorkspaceService{workspaceCollection: db.Collection("workspaces")}
+}
+
+// CreateWorkspace creates a workspace with the owner as its only member.
+func (s *WorkspaceService) CreateWorkspace(ctx context.Context, owner models.WorkspaceMember, name string) (*models.Workspace, error) {
+	now := time.Now()
+	owner.JoinedAt = now
+	owner.Role = models.RoleOwner
+	workspace := models.Workspace{
+		ID:        uuid.New().String(),
+		Name:      name,
+		OwnerID:   owner.UserID,
+		Members:   []models.WorkspaceMember{owner},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := s.workspaceCollection.InsertOne(ctx, workspace); err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// GetWorkspaces returns the workspaces the user is a member of.
+func (s *WorkspaceService) GetWorkspaces(ctx context.Context, userID string) ([]models.Workspace, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := s.workspaceCollection.Find(ctx, memberFilter(userID), opts)
+	if err != nil {
+		return nil, err
+	}
+	workspaces := []models.Workspace{}
+	if err := cursor.All(ctx, &workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// GetWorkspaceIDs returns the IDs of the workspaces the user is a member of.
+func (s *WorkspaceService) GetWorkspaceIDs(ctx context.Context, userID string) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := s.workspaceCollection.Find(ctx, memberFilter(userID), opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
+// GetWorkspace returns the workspace if the user is a member of it, or
+// mongo.ErrNoDocuments.
+func (s *WorkspaceService) GetWorkspace(ctx context.Context, id string, userID string) (*models.Workspace, error) {
+	filter := memberFilter(userID)
+	filter["_id"] = id
+	var workspace models.Workspace
+	if err := s.workspaceCollection.FindOne(ctx, filter).Decode(&workspace); err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// AddMember adds a user to the workspace, as a member unless another role is
+// given. Permissions are checked by the caller.
+func (s *WorkspaceService) AddMember(ctx context.Context, id string, member models.WorkspaceMember) (*models.Workspace, error) {
+	if member.Role == "" {
+		member.Role = models.RoleMember
+	}
+	member.JoinedAt = time.Now()
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$eq": nil}, "members.user_id": bson.M{"$ne": member.UserID}}
+	update := bson.M{
+		"$push": bson.M{"members": member},
+		"$set":  bson.M{"updated_at": member.JoinedAt},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Workspace
+	err := s.workspaceCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAlreadyMember
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// RemoveMember removes a user from the workspace. The owner cannot be removed.
+// Permissions are checked by the caller.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, workspace *models.Workspace, memberID string) error {
+	if memberID == workspace.OwnerID {
+		return ErrOwnerCannotLeave
+	}
+	update := bson.M{
+		"$pull": bson.M{"members": bson.M{"user_id": memberID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	res, err := s.workspaceCollection.UpdateOne(ctx, bson.M{"_id": workspace.ID, "members.user_id": memberID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetMemberRole changes the role of a member. The role of the owner is fixed.
+// Permissions are checked by the caller.
+func (s *WorkspaceService) SetMemberRole(ctx context.Context, workspace *models.Workspace, memberID string, role string) (*models.Workspace, error) {
+	if memberID == workspace.OwnerID {
+		return nil, ErrOwnerRoleFixed
+	}
+	filter := bson.M{"_id": workspace.ID, "members.user_id": memberID}
+	update := bson.M{"$set": bson.M{"members.$.role": role, "updated_at": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.Workspace
+	if err := s.workspaceCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func memberFilter(userID string) bson.M {
+	return bson.M{"members.user_id": userID, "deleted_at": bson.M{"$eq": nil}}
+}
@@ -4,7 +4,10 @@ import (
 	"TimeTrack-cli/src/config"
 	"TimeTrack-cli/src/database"
 	"TimeTrack-cli/src/services"
+	"TimeTrack-cli/src/utils"
+	"TimeTrack-shared/dtos"
 	"fmt"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
@@ -13,6 +16,9 @@ type AppContext struct {
 	Version string
 	DB      *database.DBWrapper
 	API     *services.APIService
+	Offline bool // set when the server could not be reached on startup
+
+	location *time.Location
 }
 
 func NewAppContext(version string) *AppContext {
@@ -38,6 +44,9 @@ func (a *AppContext) Startup(c *cli.Context) error {
 	healthResponse, err := a.API.HealthCheck()
 	if err != nil {
 		fmt.Printf("Warning: API health check failed: %s\n", err)
+		a.Offline = services.IsUnreachable(err)
+	} else {
+		a.syncPendingOperations()
 	}
 
 	if healthResponse != nil {
@@ -53,6 +62,60 @@ func (a *AppContext) Startup(c *cli.Context) error {
 	return nil
 }
 
+func (a *AppContext) syncPendingOperations() {
+	if !a.API.HasAuthToken() {
+		return
+	}
+
+	result, err := a.API.SyncPendingOperations()
+	if result != nil && result.Synced > 0 {
+		fmt.Printf("Synced %d offline change(s).\n", result.Synced)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to sync offline changes: %s\n", err)
+	}
+	if result != nil && len(result.Conflicts) > 0 {
+		fmt.Printf("Warning: %d offline change(s) conflict with the server and were not applied:\n", len(result.Conflicts))
+		for _, op := range result.Conflicts {
+			fmt.Printf("  - %s %s: %s\n", op.Type, op.EntryRef(), op.Conflict)
+		}
+		fmt.Println("Review them with `timetrack list` and discard them with (X).")
+	}
+}
+
+// Location returns the timezone of the logged in user, used to interpret dates
+// and times given on the command line. Like the server, it falls back to UTC
+// when the user has none or it cannot be loaded, so both read dates the same
+// way. The server being unreachable also leaves it at UTC.
+func (a *AppContext) Location() *time.Location {
+	if a.location != nil {
+		return a.location
+	}
+	a.location = time.UTC
+	if user, err := a.API.GetCurrentUser(); err == nil && user.Timezone != "" {
+		if loc, err := time.LoadLocation(user.Timezone); err == nil {
+			a.location = loc
+		}
+	}
+	return a.location
+}
+
+// DetectTimezone stores the timezone of this machine on the user when the user
+// has none yet.
+func (a *AppContext) DetectTimezone() {
+	user, err := a.API.GetCurrentUser()
+	if err != nil || user.Timezone != "" {
+		return
+	}
+	tz := utils.DetectTimezone()
+	if tz == "" {
+		return
+	}
+	if _, err := a.API.UpdateCurrentUser(&dtos.UpdateUserInput{Timezone: &tz}); err == nil {
+		a.location = nil
+	}
+}
+
 func (a *AppContext) Shutdown() {
 	if a.DB != nil {
 		err := a.DB.Close()
@@ -2,11 +2,18 @@ package commands
 
 import (
 	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/services"
+	"TimeTrack-cli/src/ui"
+	"TimeTrack-cli/src/ui/screens"
 	"TimeTrack-cli/src/utils"
 	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
 
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -19,7 +26,7 @@ func getAddTimeEntryCommand(ctx *app.AppContext) *cli.Command {
 		Usage:   "Add a new time entry",
 		Flags:   addTimeEntryFlags(),
 		Action: func(c *cli.Context) error {
-			if _, err := ctx.API.GetCurrentUser(); err != nil {
+			if _, err := ctx.API.GetCurrentUser(); err != nil && !services.IsUnreachable(err) {
 				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
 			}
 
@@ -32,16 +39,20 @@ func getAddTimeEntryCommand(ctx *app.AppContext) *cli.Command {
 				endDate = c.String("date")
 			}
 
-			startTimeStr := fmt.Sprintf("%sT%s:00+02:00", c.String("date"), c.String("start"))
-			endTimeStr := fmt.Sprintf("%sT%s:00+02:00", endDate, c.String("end"))
-
-			project, err := getOrCreateProject(ctx, c.String("name"))
+			var project *models.Project
+			var err error
+			if c.Bool("pick") {
+				project, err = pickJiraProject(ctx, c.String("name"))
+			} else {
+				project, err = getOrCreateProject(ctx, c.String("name"))
+			}
 			if err != nil {
 				return err
 			}
 
-			startTimeParsed, _ := time.Parse(time.RFC3339, startTimeStr)
-			endTimeParsed, _ := time.Parse(time.RFC3339, endTimeStr)
+			loc := ctx.Location()
+			startTimeParsed, _ := time.ParseInLocation("2006-01-02 15:04", c.String("date")+" "+c.String("start"), loc)
+			endTimeParsed, _ := time.ParseInLocation("2006-01-02 15:04", endDate+" "+c.String("end"), loc)
 			if endTimeParsed.Before(startTimeParsed) {
 				return cli.Exit("End time is before start time.", 1)
 			}
@@ -49,6 +60,7 @@ func getAddTimeEntryCommand(ctx *app.AppContext) *cli.Command {
 			entry := &dtos.CreateTimeEntryInput{
 				ProjectID: project.ID,
 				Note:      c.String("description"),
+				Tags:      c.StringSlice("tag"),
 				Period: dtos.TimePeriod{
 					Start: startTimeParsed,
 					End:   endTimeParsed,
@@ -60,11 +72,28 @@ func getAddTimeEntryCommand(ctx *app.AppContext) *cli.Command {
 					getTimeEntryInformationString(project, entry)+
 					"\n\nDo you want to proceed?",
 			) {
-				if _, err := ctx.API.CreateTimeEntry(entry); err != nil {
+				created, err := ctx.API.CreateTimeEntry(entry)
+				if err != nil {
+					if errors.Is(err, services.ErrQueuedOffline) {
+						fmt.Println("Server is unreachable. The time entry was saved locally and will be synced on the next run while online.")
+						fmt.Println(getTimeEntryInformationString(project, entry))
+						return nil
+					}
+					if errors.Is(err, services.ErrEntryLocked) {
+						return cli.Exit("The timesheet of the week is submitted or approved. Time Entry not created.", 1)
+					}
+					var overlapErr *services.OverlapError
+					if errors.As(err, &overlapErr) {
+						return resolveOverlap(ctx, project, entry, overlapErr.Conflicts)
+					}
 					return cli.Exit("Failed to create time entry: "+err.Error(), 1)
 				}
 				fmt.Println("Time Entry created with the following details:")
 				fmt.Println(getTimeEntryInformationString(project, entry))
+				if len(created.Conflicts) > 0 {
+					fmt.Println("\nWarning: the time entry overlaps the following entries:")
+					fmt.Println(getConflictsString(ctx, created.Conflicts))
+				}
 			} else {
 				fmt.Println("Time Entry not created.")
 			}
@@ -74,13 +103,20 @@ func getAddTimeEntryCommand(ctx *app.AppContext) *cli.Command {
 	}
 }
 
+// gitLabIssuePattern matches GitLab issue references such as "group/project#12".
+var gitLabIssuePattern = regexp.MustCompile(`^[\w.-]+(/[\w.-]+)+#[0-9]+$`)
+
 func addTimeEntryFlags() []cli.Flag {
 	return []cli.Flag{
 		&cli.StringFlag{
-			Name:     "name",
-			Aliases:  []string{"n"},
-			Required: true,
-			Usage:    "Name of task",
+			Name:    "name",
+			Aliases: []string{"n"},
+			Usage:   "Name of task. Required unless --pick is used",
+		},
+		&cli.BoolFlag{
+			Name:    "pick",
+			Aliases: []string{"p"},
+			Usage:   "Pick a Jira issue to log time on, searching for the name if given",
 		},
 		&cli.StringFlag{
 			Name:    "description",
@@ -88,6 +124,11 @@ func addTimeEntryFlags() []cli.Flag {
 			Value:   "",
 			Usage:   "Description of time entry",
 		},
+		&cli.StringSliceFlag{
+			Name:    "tag",
+			Aliases: []string{"t"},
+			Usage:   "Tag of time entry, such as meeting or code-review. Can be given more than once",
+		},
 		&cli.StringFlag{
 			Name:     "start",
 			Aliases:  []string{"s"},
@@ -120,6 +161,9 @@ func addTimeEntryFlags() []cli.Flag {
 }
 
 func validateTimeEntryInputs(c *cli.Context) error {
+	if c.String("name") == "" && !c.Bool("pick") {
+		return cli.Exit("Please provide the name of the task with --name, or pick a Jira issue with --pick.", 1)
+	}
 	if !utils.IsValidTime(c.String("start")) {
 		return cli.Exit("Invalid start time. Please use the following format: HH:mm", 1)
 	}
@@ -137,17 +181,28 @@ func validateTimeEntryInputs(c *cli.Context) error {
 
 func getOrCreateProject(ctx *app.AppContext, name string) (*models.Project, error) {
 	project, err := ctx.API.GetProjectByName(name)
+	if err != nil && services.IsUnreachable(err) {
+		return nil, cli.Exit("Server is unreachable and project '"+name+"' is not known locally. Log time against it once while online first.", 1)
+	}
 	if project == nil || err != nil {
 		if !utils.Confirm("Project not found. Do you want to create a new project with the name '" + name + "'?") {
 			return nil, cli.Exit("Project creation aborted.", 1)
 		}
 
 		projectInput := &dtos.CreateProjectInput{Name: name}
+		if gitLabIssuePattern.MatchString(name) {
+			if user, err := ctx.API.GetCurrentUser(); err == nil && user.Integration.GitLab.Enabled {
+				projectInput.Integration = dtos.IntegrationInfo{Type: "gitlab", Key: name}
+			}
+		}
 		project, err = ctx.API.CreateProject(projectInput)
 		if err != nil {
 			return nil, cli.Exit("Failed to create project: "+err.Error(), 1)
 		}
 		fmt.Printf("Project created: %s\n", project.Name)
+		if project.Integration.Type != "" {
+			fmt.Printf("Linked to %s issue %s\n", project.Integration.Type, project.Integration.Key)
+		}
 	}
 
 	if project.ID == "" {
@@ -156,17 +211,160 @@ func getOrCreateProject(ctx *app.AppContext, name string) (*models.Project, erro
 	return project, nil
 }
 
+// pickJiraProject opens the issue picker and returns the project for the chosen
+// issue, creating one bound to the issue if there is none yet.
+func pickJiraProject(ctx *app.AppContext, query string) (*models.Project, error) {
+	var picked *models.Issue
+	nav := ui.NewNavigator()
+	err := nav.Run(screens.IssuePickerScreen(nav, ctx, query,
+		func(issue models.Issue) {
+			picked = &issue
+			nav.Stop()
+		},
+		nav.Stop,
+	))
+	if err != nil {
+		return nil, cli.Exit("Failed to open issue picker: "+err.Error(), 1)
+	}
+	if picked == nil {
+		return nil, cli.Exit("No issue selected. Time Entry not created.", 1)
+	}
+
+	project, err := ctx.API.GetProjectByName(picked.Key)
+	if err == nil && project != nil && project.ID != "" {
+		return project, nil
+	}
+	if err != nil && services.IsUnreachable(err) {
+		return nil, cli.Exit("Server is unreachable. Failed to look up project '"+picked.Key+"'.", 1)
+	}
+
+	project, err = ctx.API.CreateProject(&dtos.CreateProjectInput{
+		Name: picked.Key,
+		Integration: dtos.IntegrationInfo{
+			Type:       "jira",
+			Key:        picked.Key,
+			ExternalID: picked.ID,
+		},
+	})
+	if err != nil {
+		return nil, cli.Exit("Failed to create project: "+err.Error(), 1)
+	}
+	fmt.Printf("Project created: %s (%s)\n", project.Name, picked.Summary)
+	fmt.Printf("Linked to %s issue %s\n", project.Integration.Type, project.Integration.Key)
+	return project, nil
+}
+
 func getTimeEntryInformationString(project *models.Project, entry *dtos.CreateTimeEntryInput) string {
 	note := entry.Note
 	if note == "" {
 		note = "(no description provided)"
 	}
 
-	return fmt.Sprintf(
+	info := fmt.Sprintf(
 		"Project: %s\nDescription: %s\nStart: %s\nEnd: %s",
 		project.Name,
 		note,
 		utils.FormatDate(entry.Period.Start.Format(time.RFC3339), time.RFC3339),
 		utils.FormatDate(entry.Period.End.Format(time.RFC3339), time.RFC3339),
 	)
+	if len(entry.Tags) > 0 {
+		info += "\nTags: " + strings.Join(entry.Tags, ", ")
+	}
+	return info
+}
+
+// resolveOverlap shows the entries that the rejected entry overlaps and offers
+// to log only the time that is still free, trimming the entry or splitting it
+// around the conflicts.
+func resolveOverlap(ctx *app.AppContext, project *models.Project, entry *dtos.CreateTimeEntryInput, conflicts []models.TimeEntry) error {
+	if len(conflicts) == 0 {
+		return cli.Exit("The server rejected the time entry as overlapping without naming the entries it overlaps. Time Entry not created.", 1)
+	}
+	fmt.Println("The time entry overlaps the following entries:")
+	fmt.Println(getConflictsString(ctx, conflicts))
+
+	free := freePeriods(entry.Period, conflicts)
+	if len(free) == 0 {
+		return cli.Exit("The time entry is fully covered by existing entries. Time Entry not created.", 1)
+	}
+
+	action := "Trim the time entry to"
+	if len(free) > 1 {
+		action = fmt.Sprintf("Split the time entry into %d entries for", len(free))
+	}
+	var periods strings.Builder
+	for _, period := range free {
+		fmt.Fprintf(&periods, "\n  %s - %s", period.Start.In(ctx.Location()).Format("2006-01-02 15:04"), period.End.In(ctx.Location()).Format("2006-01-02 15:04"))
+	}
+	if !utils.Confirm(fmt.Sprintf("\n%s the free time?%s", action, periods.String())) {
+		fmt.Println("Time Entry not created.")
+		return nil
+	}
+
+	for _, period := range free {
+		part := *entry
+		part.Period = period
+		if _, err := ctx.API.CreateTimeEntry(&part); err != nil {
+			return cli.Exit("Failed to create time entry: "+err.Error(), 1)
+		}
+		fmt.Println("Time Entry created with the following details:")
+		fmt.Println(getTimeEntryInformationString(project, &part))
+	}
+	return nil
+}
+
+// freePeriods returns the parts of period that none of the conflicts cover.
+func freePeriods(period dtos.TimePeriod, conflicts []models.TimeEntry) []dtos.TimePeriod {
+	sorted := append([]models.TimeEntry(nil), conflicts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Period.Started.Before(sorted[j].Period.Started)
+	})
+
+	var free []dtos.TimePeriod
+	cursor := period.Start
+	for _, conflict := range sorted {
+		if conflict.Period.Started.After(cursor) {
+			end := conflict.Period.Started
+			if end.After(period.End) {
+				end = period.End
+			}
+			free = append(free, dtos.TimePeriod{Start: cursor, End: end})
+		}
+		if conflict.Period.Ended.After(cursor) {
+			cursor = conflict.Period.Ended
+		}
+		if !cursor.Before(period.End) {
+			return free
+		}
+	}
+	return append(free, dtos.TimePeriod{Start: cursor, End: period.End})
+}
+
+func getConflictsString(ctx *app.AppContext, conflicts []models.TimeEntry) string {
+	ids := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		ids = append(ids, conflict.ProjectID)
+	}
+	names := make(map[string]string)
+	if projects, err := ctx.API.GetProjectByIds(ids); err == nil {
+		for _, p := range projects {
+			names[p.ID] = p.Name
+		}
+	}
+
+	lines := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		name := names[conflict.ProjectID]
+		if name == "" {
+			name = conflict.ProjectID
+		}
+		line := fmt.Sprintf("  - %s: %s - %s", name,
+			conflict.Period.Started.In(ctx.Location()).Format("2006-01-02 15:04"),
+			conflict.Period.Ended.In(ctx.Location()).Format("2006-01-02 15:04"))
+		if conflict.Note != "" {
+			line += " (" + conflict.Note + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
 }
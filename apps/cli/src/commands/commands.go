@@ -9,9 +9,22 @@ import (
 func GetAllCommands(ctx *app.AppContext) []*cli.Command {
 	return []*cli.Command{
 		getAddTimeEntryCommand(ctx),
+		getExportCommand(ctx),
+		getImportCommand(ctx),
+		getImportJiraCommand(ctx),
 		getListTimeEntriesCommand(ctx),
 		getLoginCommand(ctx),
+		getLogoutCommand(ctx),
+		getReconcileCommand(ctx),
+		getReportCommand(ctx),
 		getRegisterCommand(ctx),
+		getSessionsCommand(ctx),
 		getSettingsCommand(ctx),
+		getStartTimerCommand(ctx),
+		getStopTimerCommand(ctx),
+		getSubmitCommand(ctx),
+		getTimerStatusCommand(ctx),
+		getTokenCommand(ctx),
+		getWorkspaceCommand(ctx),
 	}
 }
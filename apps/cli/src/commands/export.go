@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/utils"
+
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getExportCommand(ctx *app.AppContext) *cli.Command {
+	now := time.Now()
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export time entries to a CSV or Excel file",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "from",
+				Aliases: []string{"s"},
+				Value:   firstOfMonth.Format("2006-01-02"),
+				Usage:   "First date to export. (format: YYYY-MM-DD)",
+			},
+			&cli.StringFlag{
+				Name:    "to",
+				Aliases: []string{"e"},
+				Value:   now.Format("2006-01-02"),
+				Usage:   "Last date to export. (format: YYYY-MM-DD)",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "File format, csv or xlsx. Defaults to the extension of --out, or csv.",
+			},
+			&cli.StringFlag{
+				Name:    "out",
+				Aliases: []string{"o"},
+				Usage:   "File to write. Defaults to time-entries-<from>-<to>.<format> in the current directory.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+
+			if !utils.IsValidDate(c.String("from")) || !utils.IsValidDate(c.String("to")) {
+				return cli.Exit("Invalid date. Please use the following format: YYYY-MM-DD", 1)
+			}
+			from, _ := time.ParseInLocation("2006-01-02", c.String("from"), ctx.Location())
+			to, _ := time.ParseInLocation("2006-01-02", c.String("to"), ctx.Location())
+			if to.Before(from) {
+				return cli.Exit("End date is before start date.", 1)
+			}
+			to = to.Add(24*time.Hour - time.Second)
+
+			out := c.String("out")
+			format := strings.ToLower(c.String("format"))
+			if format == "" {
+				format = strings.TrimPrefix(strings.ToLower(filepath.Ext(out)), ".")
+			}
+			if format == "" {
+				format = "csv"
+			}
+			if format != "csv" && format != "xlsx" {
+				return cli.Exit("Invalid format. Please use csv or xlsx.", 1)
+			}
+			if out == "" {
+				out = fmt.Sprintf("time-entries-%s-%s.%s", c.String("from"), c.String("to"), format)
+			}
+
+			file, err := os.Create(out)
+			if err != nil {
+				return cli.Exit("Failed to create file: "+err.Error(), 1)
+			}
+
+			err = ctx.API.ExportTimeEntries(format, from.Format(time.RFC3339), to.Format(time.RFC3339), file)
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(out)
+				return cli.Exit("Failed to export time entries: "+err.Error(), 1)
+			}
+
+			fmt.Printf("Exported time entries from %s to %s to %s\n", c.String("from"), c.String("to"), out)
+			return nil
+		},
+	}
+}
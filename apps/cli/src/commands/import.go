@@ -0,0 +1,276 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/utils"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// importFields are the fields that can be mapped to CSV columns. The column
+// name defaults to the field name.
+var importFields = []string{"project", "date", "start", "end", "note"}
+
+func getImportCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import time entries from a CSV file",
+		ArgsUsage: "<file.csv>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "columns",
+				Aliases: []string{"c"},
+				Usage:   "Map fields to CSV columns, e.g. \"project=Task,date=Day,start=From,end=To,note=Comment\". Fields: project, date, start, end, note",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only validate the file, do not import anything",
+			},
+			&cli.BoolFlag{
+				Name:  "create-projects",
+				Usage: "Create projects that do not exist yet",
+			},
+			&cli.BoolFlag{
+				Name:  "report",
+				Usage: "Report imported entries to the linked integrations",
+			},
+			&cli.BoolFlag{
+				Name:    "skipConfirmation",
+				Aliases: []string{"y"},
+				Usage:   "Skip confirmation prompt",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return cli.Exit("Please provide the CSV file to import.", 1)
+			}
+
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+
+			columns, err := parseColumnMapping(c.String("columns"))
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+
+			rows, lines, err := readImportFile(c.Args().First(), columns, ctx.Location())
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			if len(rows) == 0 {
+				return cli.Exit("The file does not contain any time entries.", 1)
+			}
+
+			input := &dtos.BulkCreateTimeEntriesInput{
+				Entries:        rows,
+				DryRun:         true,
+				CreateProjects: c.Bool("create-projects"),
+				Report:         c.Bool("report"),
+			}
+
+			// Always validate first so nothing is imported from a broken file.
+			result, err := ctx.API.BulkCreateTimeEntries(input)
+			if err != nil {
+				return cli.Exit("Failed to validate time entries: "+err.Error(), 1)
+			}
+			printImportResult(result, rows, lines)
+			if result.Invalid > 0 {
+				return cli.Exit(fmt.Sprintf("%d of %d rows are invalid. Nothing was imported.", result.Invalid, len(rows)), 1)
+			}
+			if c.Bool("dry-run") {
+				return nil
+			}
+
+			if !c.Bool("skipConfirmation") && !utils.Confirm(fmt.Sprintf("Import %d time entries?", result.Valid)) {
+				fmt.Println("Import cancelled.")
+				return nil
+			}
+
+			input.DryRun = false
+			result, err = ctx.API.BulkCreateTimeEntries(input)
+			if err != nil {
+				return cli.Exit("Failed to import time entries: "+err.Error(), 1)
+			}
+			if result.Invalid > 0 {
+				printImportResult(result, rows, lines)
+				return cli.Exit("The server rejected the import. Nothing was imported.", 1)
+			}
+
+			fmt.Printf("Imported %d time entries.\n", result.Inserted)
+			return nil
+		},
+	}
+}
+
+func parseColumnMapping(mapping string) (map[string]string, error) {
+	columns := make(map[string]string, len(importFields))
+	for _, field := range importFields {
+		columns[field] = field
+	}
+	if mapping == "" {
+		return columns, nil
+	}
+
+	for _, pair := range strings.Split(mapping, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected field=column", pair)
+		}
+		if _, known := columns[field]; !known {
+			return nil, fmt.Errorf("unknown field %q, expected one of %s", field, strings.Join(importFields, ", "))
+		}
+		columns[field] = strings.TrimSpace(column)
+	}
+	return columns, nil
+}
+
+// readImportFile parses the CSV file into bulk rows. The returned lines hold
+// the line number in the file for each row, so server errors can refer to it.
+func readImportFile(path string, columns map[string]string, loc *time.Location) ([]dtos.BulkTimeEntryRow, []int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read header row: %w", err)
+	}
+
+	index := make(map[string]int, len(importFields))
+	for field, column := range columns {
+		index[field] = -1
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), column) {
+				index[field] = i
+				break
+			}
+		}
+	}
+	for _, field := range []string{"project", "start", "end"} {
+		if index[field] < 0 {
+			return nil, nil, fmt.Errorf("column %q for %s not found in header", columns[field], field)
+		}
+	}
+
+	var rows []dtos.BulkTimeEntryRow
+	var lines []int
+	var parseErrors []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(field string) string {
+			if i := index[field]; i >= 0 && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		start, err := parseImportTime(value("date"), value("start"), loc)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("line %d: invalid start: %v", line, err))
+			continue
+		}
+		end, err := parseImportTime(value("date"), value("end"), loc)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("line %d: invalid end: %v", line, err))
+			continue
+		}
+
+		rows = append(rows, dtos.BulkTimeEntryRow{
+			ProjectName: value("project"),
+			Note:        value("note"),
+			Period:      dtos.TimePeriod{Start: start, End: end},
+		})
+		lines = append(lines, line)
+	}
+
+	if len(parseErrors) > 0 {
+		return nil, nil, errors.New(strings.Join(parseErrors, "\n"))
+	}
+	return rows, lines, nil
+}
+
+// parseImportTime accepts RFC 3339 timestamps, "YYYY-MM-DD HH:MM", or a
+// "HH:MM" time combined with the date column. Times without an offset are in
+// loc.
+func parseImportTime(date, value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("empty value")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, loc); err == nil {
+		return t, nil
+	}
+	if date == "" {
+		return time.Time{}, fmt.Errorf("%q is not a full timestamp and no date column is set", value)
+	}
+	if !utils.IsValidDate(date) || !utils.IsValidTime(value) {
+		return time.Time{}, fmt.Errorf("expected date YYYY-MM-DD and time HH:MM, got %q %q", date, value)
+	}
+	return time.ParseInLocation("2006-01-02 15:04", date+" "+value, loc)
+}
+
+func printImportResult(result *models.BulkTimeEntriesResult, rows []dtos.BulkTimeEntryRow, lines []int) {
+	var created []string
+	seen := make(map[string]bool)
+	lineOf := func(row int) int {
+		if row >= 0 && row < len(lines) {
+			return lines[row]
+		}
+		return row
+	}
+	for _, res := range result.Rows {
+		line := lineOf(res.Row)
+		for _, msg := range res.Errors {
+			if msg == models.BulkRowOverlapError && len(res.Overlaps) > 0 {
+				others := make([]string, 0, len(res.Overlaps))
+				for _, row := range res.Overlaps {
+					others = append(others, strconv.Itoa(lineOf(row)))
+				}
+				msg = "overlaps line " + strings.Join(others, ", ")
+			}
+			fmt.Printf("line %d: %s\n", line, msg)
+		}
+		if res.ProjectCreated && res.Row >= 0 && res.Row < len(rows) {
+			name := rows[res.Row].ProjectName
+			if !seen[strings.ToLower(name)] {
+				seen[strings.ToLower(name)] = true
+				created = append(created, name)
+			}
+		}
+	}
+
+	fmt.Printf("%d valid, %d invalid rows.\n", result.Valid, result.Invalid)
+	if len(created) > 0 {
+		fmt.Printf("New projects: %s\n", strings.Join(created, ", "))
+	}
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/utils"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getImportJiraCommand(ctx *app.AppContext) *cli.Command {
+	now := time.Now()
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	return &cli.Command{
+		Name:  "import-jira",
+		Usage: "Import your Jira worklogs as time entries",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "from",
+				Aliases: []string{"s"},
+				Value:   firstOfMonth.AddDate(0, -3, 0).Format("2006-01-02"),
+				Usage:   "First date to import. (format: YYYY-MM-DD)",
+			},
+			&cli.StringFlag{
+				Name:    "to",
+				Aliases: []string{"e"},
+				Value:   now.Format("2006-01-02"),
+				Usage:   "Last date to import. (format: YYYY-MM-DD)",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only show what would be imported",
+			},
+			&cli.BoolFlag{
+				Name:    "skipConfirmation",
+				Aliases: []string{"y"},
+				Usage:   "Skip confirmation prompt",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			user, err := ctx.API.GetCurrentUser()
+			if err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+			if !user.Integration.Atlassian.Enabled {
+				return cli.Exit("The Atlassian integration is not enabled. Authenticate with Atlassian in the settings first.", 1)
+			}
+
+			if !utils.IsValidDate(c.String("from")) || !utils.IsValidDate(c.String("to")) {
+				return cli.Exit("Invalid date. Please use the following format: YYYY-MM-DD", 1)
+			}
+			loc := ctx.Location()
+			from, _ := time.ParseInLocation("2006-01-02", c.String("from"), loc)
+			to, _ := time.ParseInLocation("2006-01-02", c.String("to"), loc)
+			if to.Before(from) {
+				return cli.Exit("End date is before start date.", 1)
+			}
+			to = to.Add(24*time.Hour - time.Second)
+
+			input := &dtos.ImportWorklogsInput{From: from, To: to, DryRun: true}
+			fmt.Println("Fetching worklogs from Jira...")
+			result, err := ctx.API.ImportJiraWorklogs(input)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			printWorklogImportResult(ctx, result)
+			if result.Imported == 0 || c.Bool("dry-run") {
+				return nil
+			}
+
+			if !c.Bool("skipConfirmation") && !utils.Confirm(fmt.Sprintf("Import %d worklogs as time entries?", result.Imported)) {
+				fmt.Println("Import cancelled.")
+				return nil
+			}
+
+			input.DryRun = false
+			result, err = ctx.API.ImportJiraWorklogs(input)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			fmt.Printf("Imported %d worklogs as time entries.\n", result.Imported)
+			return nil
+		},
+	}
+}
+
+func printWorklogImportResult(ctx *app.AppContext, result *models.WorklogImportResult) {
+	fmt.Printf("Found %d worklogs, %d new and %d imported before.\n", result.Worklogs, result.Imported, result.Skipped)
+	if len(result.ProjectsCreated) > 0 {
+		fmt.Printf("New projects: %s\n", strings.Join(result.ProjectsCreated, ", "))
+	}
+
+	var total int
+	for _, entry := range result.Entries {
+		total += entry.Period.Duration
+	}
+	if total > 0 {
+		fmt.Printf("Time to import: %dh%02dm, from %s to %s\n", total/3600, total%3600/60,
+			result.Entries[0].Period.Started.In(ctx.Location()).Format("2006-01-02"),
+			result.Entries[len(result.Entries)-1].Period.Started.In(ctx.Location()).Format("2006-01-02"))
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("Not imported:\n  %s\n", strings.Join(result.Errors, "\n  "))
+	}
+}
@@ -39,14 +39,14 @@ func getListTimeEntriesCommand(ctx *app.AppContext) *cli.Command {
 			},
 		},
 		Action: func(c *cli.Context) error {
-			startDate, err := time.Parse("2006-01-02", c.String("start"))
+			startDate, err := time.ParseInLocation("2006-01-02", c.String("start"), ctx.Location())
 			if err != nil {
 				return fmt.Errorf("invalid start date: %v", err)
 			}
 
 			endDate := startDate
 			if c.String("end") != "" {
-				endDate, err = time.Parse("2006-01-02", c.String("end"))
+				endDate, err = time.ParseInLocation("2006-01-02", c.String("end"), ctx.Location())
 				if err != nil {
 					return fmt.Errorf("invalid end date: %v", err)
 				}
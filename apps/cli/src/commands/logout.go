@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getLogoutCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "logout",
+		Usage: "Log out and end the session on the server",
+		Action: func(c *cli.Context) error {
+			if err := ctx.API.Logout(); err != nil {
+				fmt.Printf("Warning: %s\n", err)
+			}
+			fmt.Println("Logged out.")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/utils"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// driftActions describes what pulling and pushing does for each kind of drift.
+var driftActions = map[string]map[string]string{
+	models.DriftMissing: {
+		"pull": "delete the time entry, like the worklog was in Jira",
+		"push": "log the time entry in Jira again",
+	},
+	models.DriftChanged: {
+		"pull": "use the duration from Jira for the time entry",
+		"push": "update the worklog in Jira to match the time entry",
+	},
+	models.DriftOrphaned: {
+		"pull": "create a time entry for the worklog",
+		"push": "delete the worklog from Jira",
+	},
+}
+
+func getReconcileCommand(ctx *app.AppContext) *cli.Command {
+	now := time.Now()
+
+	return &cli.Command{
+		Name:  "reconcile",
+		Usage: "Find and fix differences between Jira worklogs and reported time entries",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "from",
+				Aliases: []string{"s"},
+				Value:   now.AddDate(0, 0, -30).Format("2006-01-02"),
+				Usage:   "First date to check. (format: YYYY-MM-DD)",
+			},
+			&cli.StringFlag{
+				Name:    "to",
+				Aliases: []string{"e"},
+				Value:   now.Format("2006-01-02"),
+				Usage:   "Last date to check. (format: YYYY-MM-DD)",
+			},
+			&cli.BoolFlag{
+				Name:  "pull",
+				Usage: "Resolve every difference by taking the state from Jira",
+			},
+			&cli.BoolFlag{
+				Name:  "push",
+				Usage: "Resolve every difference by sending the local state to Jira",
+			},
+			&cli.BoolFlag{
+				Name:    "skipConfirmation",
+				Aliases: []string{"y"},
+				Usage:   "Skip confirmation prompt when using --pull or --push",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+			if c.Bool("pull") && c.Bool("push") {
+				return cli.Exit("Please use either --pull or --push, not both.", 1)
+			}
+
+			if !utils.IsValidDate(c.String("from")) || !utils.IsValidDate(c.String("to")) {
+				return cli.Exit("Invalid date. Please use the following format: YYYY-MM-DD", 1)
+			}
+			loc := ctx.Location()
+			from, _ := time.ParseInLocation("2006-01-02", c.String("from"), loc)
+			to, _ := time.ParseInLocation("2006-01-02", c.String("to"), loc)
+			if to.Before(from) {
+				return cli.Exit("End date is before start date.", 1)
+			}
+			to = to.Add(24*time.Hour - time.Second)
+
+			report, err := ctx.API.ReconcileJira(from.Format(time.RFC3339), to.Format(time.RFC3339))
+			if err != nil {
+				return cli.Exit("Failed to reconcile Jira worklogs: "+err.Error(), 1)
+			}
+			fmt.Printf("Compared %d reported time entries with %d Jira worklogs.\n", report.Entries, report.Worklogs)
+			if len(report.Drift) == 0 {
+				fmt.Println("Everything is in sync.")
+				return nil
+			}
+
+			names := getDriftProjectNames(ctx, report.Drift)
+			fmt.Printf("Found %d differences:\n", len(report.Drift))
+			for _, drift := range report.Drift {
+				fmt.Println(getDriftString(ctx, drift, names))
+			}
+			fmt.Println()
+
+			var items []dtos.DriftResolution
+			if action := bulkDriftAction(c); action != "" {
+				if !c.Bool("skipConfirmation") && !utils.Confirm(fmt.Sprintf("Resolve all %d differences with %s?", len(report.Drift), action)) {
+					fmt.Println("Nothing was changed.")
+					return nil
+				}
+				for _, drift := range report.Drift {
+					items = append(items, driftResolution(drift, action))
+				}
+			} else {
+				for _, drift := range report.Drift {
+					fmt.Println(getDriftString(ctx, drift, names))
+					choice := utils.Choose("How should this difference be resolved?", map[string]string{
+						"p": "pull: " + driftActions[drift.Kind]["pull"],
+						"s": "push: " + driftActions[drift.Kind]["push"],
+						"k": "keep as is",
+					})
+					switch choice {
+					case "p":
+						items = append(items, driftResolution(drift, "pull"))
+					case "s":
+						items = append(items, driftResolution(drift, "push"))
+					}
+				}
+			}
+			if len(items) == 0 {
+				fmt.Println("Nothing was changed.")
+				return nil
+			}
+
+			results, err := ctx.API.ResolveJiraDrift(items)
+			if err != nil {
+				return cli.Exit("Failed to resolve differences: "+err.Error(), 1)
+			}
+			failed := 0
+			for _, result := range results {
+				if result.Error != "" {
+					failed++
+					fmt.Printf("Failed to %s %s difference: %s\n", result.Action, result.Kind, result.Error)
+				}
+			}
+			fmt.Printf("Resolved %d of %d differences.\n", len(results)-failed, len(results))
+			if failed > 0 {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+}
+
+func bulkDriftAction(c *cli.Context) string {
+	switch {
+	case c.Bool("pull"):
+		return "pull"
+	case c.Bool("push"):
+		return "push"
+	default:
+		return ""
+	}
+}
+
+func driftResolution(drift models.WorklogDrift, action string) dtos.DriftResolution {
+	item := dtos.DriftResolution{
+		Kind:      drift.Kind,
+		Action:    action,
+		ProjectID: drift.ProjectID,
+	}
+	if drift.TimeEntry != nil {
+		item.TimeEntryID = drift.TimeEntry.ID
+	}
+	if drift.Worklog != nil {
+		item.WorklogID = drift.Worklog.ID
+	}
+	return item
+}
+
+func getDriftProjectNames(ctx *app.AppContext, drift []models.WorklogDrift) map[string]string {
+	ids := make([]string, 0, len(drift))
+	for _, d := range drift {
+		ids = append(ids, d.ProjectID)
+	}
+	names := make(map[string]string)
+	if projects, err := ctx.API.GetProjectByIds(ids); err == nil {
+		for _, p := range projects {
+			names[p.ID] = p.Name
+		}
+	}
+	return names
+}
+
+func getDriftString(ctx *app.AppContext, drift models.WorklogDrift, names map[string]string) string {
+	name := names[drift.ProjectID]
+	if name == "" {
+		name = drift.IssueID
+	}
+	loc := ctx.Location()
+	formatDuration := func(seconds int) string {
+		return fmt.Sprintf("%dh%02dm", seconds/3600, seconds%3600/60)
+	}
+
+	switch drift.Kind {
+	case models.DriftMissing:
+		return fmt.Sprintf("  - [missing]  %s %s (%s): the worklog was deleted in Jira",
+			name, drift.TimeEntry.Period.Started.In(loc).Format("2006-01-02 15:04"), formatDuration(drift.TimeEntry.Period.Duration))
+	case models.DriftChanged:
+		return fmt.Sprintf("  - [changed]  %s %s: %s locally, %s in Jira",
+			name, drift.TimeEntry.Period.Started.In(loc).Format("2006-01-02 15:04"),
+			formatDuration(drift.TimeEntry.Period.Duration), formatDuration(drift.Worklog.Duration))
+	case models.DriftOrphaned:
+		line := fmt.Sprintf("  - [orphaned] %s %s (%s): the worklog has no time entry",
+			name, drift.Worklog.Started.In(loc).Format("2006-01-02 15:04"), formatDuration(drift.Worklog.Duration))
+		if drift.Worklog.Comment != "" {
+			line += " (" + drift.Worklog.Comment + ")"
+		}
+		return line
+	default:
+		return "  - [" + drift.Kind + "] " + name
+	}
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getReportCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "report",
+		Usage: "Create a PDF or HTML timesheet of a month",
+		Description: "The timesheet lists the time of the month by project and day, with notes, totals and\n" +
+			"billable amounts, and ends with a line to sign it off.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "month",
+				Aliases: []string{"m"},
+				Usage:   "Month of the timesheet (format: YYYY-MM, default: the current month)",
+			},
+			&cli.StringFlag{
+				Name:    "client",
+				Aliases: []string{"c"},
+				Usage:   "Name or ID of a client to limit the timesheet to",
+			},
+			&cli.StringFlag{
+				Name:    "user",
+				Aliases: []string{"u"},
+				Usage:   "Email of a member of the active workspace, or \"all\", instead of your own time",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "File format, pdf or html. Defaults to the extension of --out, or pdf.",
+			},
+			&cli.StringFlag{
+				Name:    "out",
+				Aliases: []string{"o"},
+				Usage:   "File to write. Defaults to timesheet-<month>.<format> in the current directory.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+
+			month := c.String("month")
+			if month == "" {
+				month = time.Now().In(ctx.Location()).Format("2006-01")
+			}
+			if _, err := time.Parse("2006-01", month); err != nil {
+				return cli.Exit("Invalid month. Please use the following format: YYYY-MM", 1)
+			}
+
+			out := c.String("out")
+			format := strings.ToLower(c.String("format"))
+			if format == "" {
+				format = strings.TrimPrefix(strings.ToLower(filepath.Ext(out)), ".")
+			}
+			switch format {
+			case "":
+				format = "pdf"
+			case "htm":
+				format = "html"
+			}
+			if format != "pdf" && format != "html" {
+				return cli.Exit("Invalid format. Please use pdf or html.", 1)
+			}
+			if out == "" {
+				out = fmt.Sprintf("timesheet-%s.%s", month, format)
+			}
+
+			clientID := ""
+			if query := strings.TrimSpace(c.String("client")); query != "" {
+				id, err := findClientID(ctx, query)
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				clientID = id
+			}
+
+			file, err := os.Create(out)
+			if err != nil {
+				return cli.Exit("Failed to create file: "+err.Error(), 1)
+			}
+
+			err = ctx.API.DownloadMonthlyReport(month, format, clientID, c.String("user"), file)
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(out)
+				return cli.Exit("Failed to create report: "+err.Error(), 1)
+			}
+
+			fmt.Printf("Wrote the timesheet of %s to %s\n", month, out)
+			return nil
+		},
+	}
+}
+
+// findClientID returns the ID of the client with the ID, or else the one whose
+// name matches, ignoring case, in the active workspace.
+func findClientID(ctx *app.AppContext, query string) (string, error) {
+	clients, err := ctx.API.GetClients()
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, client := range clients {
+		if client.ID == query {
+			return client.ID, nil
+		}
+		if strings.EqualFold(client.Name, query) {
+			matches = append(matches, client.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no client named %q", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d clients are named %q, use the ID instead", len(matches), query)
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getSessionsCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "sessions",
+		Usage: "List the active sessions of your account",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "revoke",
+				Usage: "ID of a session to end",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+
+			if id := c.String("revoke"); id != "" {
+				if err := ctx.API.RevokeSession(id); err != nil {
+					return cli.Exit("Failed to revoke session: "+err.Error(), 1)
+				}
+				fmt.Println("Session revoked.")
+				return nil
+			}
+
+			sessions, err := ctx.API.GetSessions()
+			if err != nil {
+				return cli.Exit("Failed to get sessions: "+err.Error(), 1)
+			}
+			if len(sessions) == 0 {
+				fmt.Println("No active sessions.")
+				return nil
+			}
+
+			for _, session := range sessions {
+				marker := " "
+				if session.Current {
+					marker = "*"
+				}
+				device := session.DeviceName
+				if device == "" {
+					device = "Unknown device"
+				}
+				fmt.Printf("%s %s  %s\n", marker, session.ID, device)
+				fmt.Printf("    Last used %s, expires %s\n",
+					session.LastUsedAt.Local().Format("2006-01-02 15:04"),
+					session.ExpiresAt.Local().Format("2006-01-02"))
+			}
+			return nil
+		},
+	}
+}
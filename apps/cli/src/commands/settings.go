@@ -4,6 +4,12 @@ import (
 	"TimeTrack-cli/src/app"
 	"TimeTrack-cli/src/ui"
 	"TimeTrack-cli/src/ui/screens"
+	"TimeTrack-cli/src/utils"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+
+	"fmt"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
@@ -12,9 +18,104 @@ func getSettingsCommand(ctx *app.AppContext) *cli.Command {
 	return &cli.Command{
 		Name:  "settings",
 		Usage: "Show dashboard and manage settings",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "overlap-policy",
+				Usage: "What to do when a time entry overlaps another one: reject, warn or allow",
+			},
+			&cli.BoolFlag{
+				Name:  "jira-auto-link",
+				Usage: "Link new projects to the Jira issue in their name",
+			},
+			&cli.BoolFlag{
+				Name:  "jira-fuzzy-link",
+				Usage: "Find the Jira issue key anywhere in the project name instead of requiring an exact match",
+			},
+			&cli.StringFlag{
+				Name:  "gitlab-token",
+				Usage: "Connect GitLab with a personal access token with the api scope",
+			},
+			&cli.StringFlag{
+				Name:  "gitlab-url",
+				Usage: "URL of the GitLab instance used with --gitlab-token. Defaults to the server's instance",
+			},
+			&cli.StringFlag{
+				Name:  "timezone",
+				Usage: "IANA timezone used for dates and statistics, e.g. Europe/Stockholm, or \"auto\" to detect it",
+			},
+		},
 		Action: func(c *cli.Context) error {
+			if c.IsSet("overlap-policy") {
+				return updateOverlapPolicy(ctx, c.String("overlap-policy"))
+			}
+			if c.IsSet("timezone") {
+				return updateTimezone(ctx, c.String("timezone"))
+			}
+			if c.IsSet("gitlab-token") {
+				input := &dtos.ConnectGitLabInput{BaseURL: c.String("gitlab-url"), AccessToken: c.String("gitlab-token")}
+				if err := ctx.API.ConnectGitLab(input); err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				fmt.Println("GitLab connected.")
+				return nil
+			}
+			if c.IsSet("jira-auto-link") || c.IsSet("jira-fuzzy-link") {
+				return updateAutoLinkSettings(ctx, c)
+			}
+
 			nav := ui.NewNavigator()
 			return nav.Run(screens.DashboardScreen(nav, ctx))
 		},
 	}
 }
+
+func updateOverlapPolicy(ctx *app.AppContext, policy string) error {
+	switch policy {
+	case models.OverlapPolicyReject, models.OverlapPolicyWarn, models.OverlapPolicyAllow:
+	default:
+		return cli.Exit("Invalid overlap policy. Please use reject, warn or allow.", 1)
+	}
+
+	if _, err := ctx.API.UpdateCurrentUser(&dtos.UpdateUserInput{OverlapPolicy: &policy}); err != nil {
+		return cli.Exit("Failed to update overlap policy: "+err.Error(), 1)
+	}
+	fmt.Printf("Overlap policy set to %s\n", policy)
+	return nil
+}
+
+func updateTimezone(ctx *app.AppContext, tz string) error {
+	if tz == "auto" {
+		tz = utils.DetectTimezone()
+		if tz == "" {
+			return cli.Exit("Could not detect the timezone of this machine. Please provide it, e.g. Europe/Stockholm.", 1)
+		}
+	}
+	if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+		return cli.Exit("Invalid timezone. Please use an IANA name, e.g. Europe/Stockholm.", 1)
+	}
+
+	if _, err := ctx.API.UpdateCurrentUser(&dtos.UpdateUserInput{Timezone: &tz}); err != nil {
+		return cli.Exit("Failed to update timezone: "+err.Error(), 1)
+	}
+	fmt.Printf("Timezone set to %s\n", tz)
+	return nil
+}
+
+func updateAutoLinkSettings(ctx *app.AppContext, c *cli.Context) error {
+	input := &dtos.UpdateUserSettingsInput{}
+	if c.IsSet("jira-auto-link") {
+		v := c.Bool("jira-auto-link")
+		input.AutoLinkJira = &v
+	}
+	if c.IsSet("jira-fuzzy-link") {
+		v := c.Bool("jira-fuzzy-link")
+		input.FuzzyAutoLink = &v
+	}
+
+	settings, err := ctx.API.UpdateUserSettings(input)
+	if err != nil {
+		return cli.Exit("Failed to update settings: "+err.Error(), 1)
+	}
+	fmt.Printf("Jira auto-link: %t, fuzzy matching: %t\n", settings.AutoLinkJira, settings.FuzzyAutoLink)
+	return nil
+}
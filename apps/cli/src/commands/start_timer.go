@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getStartTimerCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "start",
+		Usage: "Start a timer for a task",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "name",
+				Aliases: []string{"n"},
+				Usage:   "Name of task. Required unless --pick is used",
+			},
+			&cli.BoolFlag{
+				Name:    "pick",
+				Aliases: []string{"p"},
+				Usage:   "Pick a Jira issue to track time on, searching for the name if given",
+			},
+			&cli.StringFlag{
+				Name:    "description",
+				Aliases: []string{"desc", "D"},
+				Value:   "",
+				Usage:   "Description of time entry",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+
+			if c.String("name") == "" && !c.Bool("pick") {
+				return cli.Exit("Please provide the name of the task with --name, or pick a Jira issue with --pick.", 1)
+			}
+
+			var project *models.Project
+			var err error
+			if c.Bool("pick") {
+				project, err = pickJiraProject(ctx, c.String("name"))
+			} else {
+				project, err = getOrCreateProject(ctx, c.String("name"))
+			}
+			if err != nil {
+				return err
+			}
+
+			entry, err := ctx.API.StartTimer(&dtos.StartTimerInput{
+				ProjectID: project.ID,
+				Note:      c.String("description"),
+			})
+			if err != nil {
+				return cli.Exit("Failed to start timer: "+err.Error(), 1)
+			}
+
+			fmt.Printf("Timer started for %s at %s\n", project.Name, entry.Period.Started.In(ctx.Location()).Format("2006-01-02 15:04"))
+			return nil
+		},
+	}
+}
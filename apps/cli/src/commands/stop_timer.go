@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/services"
+	"TimeTrack-shared/dtos"
+
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getStopTimerCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "stop",
+		Usage: "Stop the running timer and report it",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "description",
+				Aliases: []string{"desc", "D"},
+				Usage:   "Description of time entry. Replaces the description given on start.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+
+			input := &dtos.StopTimerInput{}
+			if c.IsSet("description") {
+				note := c.String("description")
+				input.Note = &note
+			}
+
+			entry, err := ctx.API.StopTimer(input)
+			if err != nil {
+				var overlapErr *services.OverlapError
+				if errors.As(err, &overlapErr) {
+					fmt.Println("The timer overlaps the following entries:")
+					fmt.Println(getConflictsString(ctx, overlapErr.Conflicts))
+					return cli.Exit("Timer not stopped. Change the overlapping entries and stop it again.", 1)
+				}
+				return cli.Exit("Failed to stop timer: "+err.Error(), 1)
+			}
+
+			fmt.Println("Timer stopped. Time Entry created with the following details:")
+			fmt.Println(getTimerInformationString(ctx, entry.ProjectID, entry.Note, entry.Period.Started, entry.Period.Ended))
+			if len(entry.Conflicts) > 0 {
+				fmt.Println("\nWarning: the time entry overlaps the following entries:")
+				fmt.Println(getConflictsString(ctx, entry.Conflicts))
+			}
+			if entry.Reported != nil && entry.Reported.Done {
+				fmt.Printf("Reported to %s.\n", entry.Reported.Integration)
+			}
+			return nil
+		},
+	}
+}
+
+func getTimerInformationString(ctx *app.AppContext, projectID, note string, start, end time.Time) string {
+	projectName := projectID
+	if projects, err := ctx.API.GetProjectByIds([]string{projectID}); err == nil && len(projects) > 0 {
+		projectName = projects[0].Name
+	}
+	if note == "" {
+		note = "(no description provided)"
+	}
+
+	return fmt.Sprintf(
+		"Project: %s\nDescription: %s\nStart: %s\nEnd: %s\nDuration: %s",
+		projectName,
+		note,
+		start.In(ctx.Location()).Format("2006-01-02 15:04"),
+		end.In(ctx.Location()).Format("2006-01-02 15:04"),
+		end.Sub(start).Round(time.Second),
+	)
+}
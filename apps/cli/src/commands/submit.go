@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-shared/models"
+
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getSubmitCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "submit",
+		Usage: "Submit a week of the active workspace for approval",
+		Description: "Submitting a week locks its time entries on workspace projects until a manager rejects it.\n" +
+			"Managers can list the timesheets waiting for review and approve or reject them.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "week",
+				Aliases: []string{"w"},
+				Usage:   "ISO week to submit, e.g. 2026-W38 (default: the current week)",
+			},
+			&cli.BoolFlag{
+				Name:    "list",
+				Aliases: []string{"l"},
+				Usage:   "List your timesheets instead of submitting",
+			},
+			&cli.BoolFlag{
+				Name:  "review",
+				Usage: "List the timesheets waiting for your review",
+			},
+			&cli.StringFlag{
+				Name:  "approve",
+				Usage: "ID of a timesheet to approve",
+			},
+			&cli.StringFlag{
+				Name:  "reject",
+				Usage: "ID of a timesheet to reject, requires --comment",
+			},
+			&cli.StringFlag{
+				Name:    "comment",
+				Aliases: []string{"m"},
+				Usage:   "Comment for the owner of the reviewed timesheet",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+			workspace, err := getActiveWorkspace(ctx)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+
+			switch {
+			case c.String("approve") != "":
+				timesheet, err := ctx.API.ApproveTimesheet(c.String("approve"), c.String("comment"))
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				fmt.Printf("Approved %s of %s.\n", timesheet.Week, timesheetOwner(workspace, timesheet))
+				return nil
+			case c.String("reject") != "":
+				if strings.TrimSpace(c.String("comment")) == "" {
+					return cli.Exit("A comment is required to reject a timesheet.", 1)
+				}
+				timesheet, err := ctx.API.RejectTimesheet(c.String("reject"), c.String("comment"))
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				fmt.Printf("Rejected %s of %s.\n", timesheet.Week, timesheetOwner(workspace, timesheet))
+				return nil
+			case c.Bool("review"):
+				timesheets, err := ctx.API.GetTimesheetsForReview()
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				if len(timesheets) == 0 {
+					fmt.Println("No timesheets waiting for review.")
+					return nil
+				}
+				for _, timesheet := range timesheets {
+					fmt.Printf("%s  %s  %s  %s in %d entries\n", timesheet.ID, timesheet.Week,
+						timesheetOwner(workspace, &timesheet), formatSeconds(timesheet.TotalTime), timesheet.TotalEntries)
+				}
+				return nil
+			case c.Bool("list"):
+				timesheets, err := ctx.API.GetTimesheets()
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				if len(timesheets) == 0 {
+					fmt.Printf("No timesheets submitted in %q.\n", workspace.Name)
+					return nil
+				}
+				for _, timesheet := range timesheets {
+					fmt.Printf("%s  %-9s  %s\n", timesheet.Week, timesheet.Status, formatSeconds(timesheet.TotalTime))
+					if timesheet.Comment != "" {
+						fmt.Printf("    %s\n", timesheet.Comment)
+					}
+				}
+				return nil
+			}
+
+			week := strings.ToUpper(strings.TrimSpace(c.String("week")))
+			if week == "" {
+				year, number := time.Now().In(ctx.Location()).ISOWeek()
+				week = fmt.Sprintf("%d-W%02d", year, number)
+			}
+			timesheet, err := ctx.API.SubmitTimesheet(week)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			fmt.Printf("Submitted %s in %q: %s in %d entries, from %s to %s.\n", timesheet.Week, workspace.Name,
+				formatSeconds(timesheet.TotalTime), timesheet.TotalEntries,
+				timesheet.Start.In(ctx.Location()).Format("2006-01-02"),
+				timesheet.End.In(ctx.Location()).AddDate(0, 0, -1).Format("2006-01-02"))
+			fmt.Println("Its time entries are locked until the timesheet is rejected.")
+			return nil
+		},
+	}
+}
+
+// timesheetOwner returns the email of the owner of the timesheet, or its ID
+// when the owner has left the workspace.
+func timesheetOwner(workspace *models.Workspace, timesheet *models.Timesheet) string {
+	for _, member := range workspace.Members {
+		if member.UserID == timesheet.OwnerID {
+			return member.Email
+		}
+	}
+	return timesheet.OwnerID
+}
+
+func formatSeconds(seconds int64) string {
+	return fmt.Sprintf("%dh%02dm", seconds/3600, seconds%3600/60)
+}
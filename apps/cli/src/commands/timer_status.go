@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getTimerStatusCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "status",
+		Usage: "Show the running timer",
+		Action: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+
+			entry, err := ctx.API.GetRunningTimer()
+			if err != nil {
+				return cli.Exit("Failed to get timer: "+err.Error(), 1)
+			}
+			if entry == nil {
+				fmt.Println("No timer is running.")
+				return nil
+			}
+
+			fmt.Println("Timer running:")
+			fmt.Println(getTimerInformationString(ctx, entry.ProjectID, entry.Note, entry.Period.Started, time.Now()))
+			return nil
+		},
+	}
+}
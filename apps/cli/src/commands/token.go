@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/services"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func getTokenCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "token",
+		Usage: "Manage personal access tokens for scripts and CI",
+		Description: fmt.Sprintf("Set the %s environment variable to a token to use it instead of your login.",
+			services.TokenEnvVar),
+		Before: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+			return nil
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:  "create",
+				Usage: "Create a token",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Aliases:  []string{"n"},
+						Usage:    "Name to recognize the token by",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "scope",
+						Aliases: []string{"s"},
+						Value:   models.TokenScopeWrite,
+						Usage:   "Access of the token: read (read-only), write (read and change time entries) or admin (full access)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					input := &dtos.CreateAPITokenInput{Name: c.String("name"), Scope: c.String("scope")}
+					token, err := ctx.API.CreateAPIToken(input)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					fmt.Printf("Created token %q with %s scope:\n\n  %s\n\n", token.Name, token.Scope, token.Token)
+					fmt.Println("Store it somewhere safe, it will not be shown again.")
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List your tokens",
+				Action: func(c *cli.Context) error {
+					tokens, err := ctx.API.GetAPITokens()
+					if err != nil {
+						return cli.Exit("Failed to get tokens: "+err.Error(), 1)
+					}
+					if len(tokens) == 0 {
+						fmt.Println("No tokens.")
+						return nil
+					}
+					for _, token := range tokens {
+						lastUsed := "never"
+						if token.LastUsedAt != nil {
+							lastUsed = token.LastUsedAt.Local().Format("2006-01-02 15:04")
+						}
+						fmt.Printf("%s  %s (%s, %s...)\n", token.ID, token.Name, token.Scope, token.Prefix)
+						fmt.Printf("    Created %s, last used %s\n", token.CreatedAt.Local().Format("2006-01-02"), lastUsed)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "revoke",
+				Usage:     "Revoke a token",
+				ArgsUsage: "<id>",
+				Action: func(c *cli.Context) error {
+					id := c.Args().First()
+					if id == "" {
+						return cli.Exit("Token ID is required. Use \"token list\" to find it.", 1)
+					}
+					if err := ctx.API.RevokeAPIToken(id); err != nil {
+						return cli.Exit("Failed to revoke token: "+err.Error(), 1)
+					}
+					fmt.Println("Token revoked.")
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,309 @@
+package commands
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// personalWorkspace is the name used to switch back to personal projects.
+const personalWorkspace = "personal"
+
+func getWorkspaceCommand(ctx *app.AppContext) *cli.Command {
+	return &cli.Command{
+		Name:  "workspace",
+		Usage: "Share projects with your team",
+		Description: "Projects are created and looked up in the active workspace. Time entries stay your own,\n" +
+			"but everyone in a workspace logs time against the same projects.",
+		Before: func(c *cli.Context) error {
+			if _, err := ctx.API.GetCurrentUser(); err != nil {
+				return cli.Exit("Unauthorized or not logged in. Please login or register first.", 1)
+			}
+			return nil
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List your workspaces",
+				Action: func(c *cli.Context) error {
+					workspaces, err := ctx.API.GetWorkspaces()
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					activeID := ctx.API.GetActiveWorkspaceID()
+					fmt.Printf("%s %s\n", activeMarker(activeID == ""), personalWorkspace)
+					for _, workspace := range workspaces {
+						fmt.Printf("%s %s  (%d members, ID %s)\n", activeMarker(workspace.ID == activeID),
+							workspace.Name, len(workspace.Members), workspace.ID)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "create",
+				Usage: "Create a workspace and make it active",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Aliases:  []string{"n"},
+						Usage:    "Name of the workspace",
+						Required: true,
+					},
+				},
+				Action: func(c *cli.Context) error {
+					workspace, err := ctx.API.CreateWorkspace(&dtos.CreateWorkspaceInput{Name: c.String("name")})
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					if err := ctx.API.SetActiveWorkspace(workspace); err != nil {
+						return cli.Exit("Failed to save active workspace: "+err.Error(), 1)
+					}
+					fmt.Printf("Created workspace %q. It is now active.\n", workspace.Name)
+					return nil
+				},
+			},
+			{
+				Name:      "use",
+				Usage:     "Choose the active workspace",
+				ArgsUsage: "<name or ID, or \"" + personalWorkspace + "\">",
+				Action: func(c *cli.Context) error {
+					query := strings.TrimSpace(c.Args().First())
+					if query == "" {
+						return cli.Exit("Workspace name or ID is required.", 1)
+					}
+					if strings.EqualFold(query, personalWorkspace) {
+						if err := ctx.API.SetActiveWorkspace(nil); err != nil {
+							return cli.Exit("Failed to save active workspace: "+err.Error(), 1)
+						}
+						fmt.Println("Using personal projects.")
+						return nil
+					}
+
+					workspaces, err := ctx.API.GetWorkspaces()
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					workspace, err := findWorkspace(workspaces, query)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					if err := ctx.API.SetActiveWorkspace(workspace); err != nil {
+						return cli.Exit("Failed to save active workspace: "+err.Error(), 1)
+					}
+					fmt.Printf("Using workspace %q.\n", workspace.Name)
+					return nil
+				},
+			},
+			{
+				Name:  "members",
+				Usage: "List the members of the active workspace",
+				Action: func(c *cli.Context) error {
+					workspace, err := getActiveWorkspace(ctx)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					for _, member := range workspace.Members {
+						fmt.Printf("%s (%s), joined %s\n", member.Email, memberRole(workspace, &member),
+							member.JoinedAt.Local().Format("2006-01-02"))
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "add",
+				Usage:     "Add a registered user to the active workspace",
+				ArgsUsage: "<email>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "role",
+						Aliases: []string{"r"},
+						Value:   models.RoleMember,
+						Usage:   "Role of the user: admin, manager or member",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					email := strings.TrimSpace(c.Args().First())
+					if email == "" {
+						return cli.Exit("Email is required.", 1)
+					}
+					workspace, err := getActiveWorkspace(ctx)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					input := &dtos.AddWorkspaceMemberInput{Email: email, Role: c.String("role")}
+					if _, err := ctx.API.AddWorkspaceMember(workspace.ID, input); err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					fmt.Printf("Added %s to %q as %s.\n", email, workspace.Name, input.Role)
+					return nil
+				},
+			},
+			{
+				Name:      "role",
+				Usage:     "Change the role of a member of the active workspace",
+				ArgsUsage: "<email> <admin|manager|member>",
+				Action: func(c *cli.Context) error {
+					email, role := strings.TrimSpace(c.Args().Get(0)), strings.TrimSpace(c.Args().Get(1))
+					if email == "" || role == "" {
+						return cli.Exit("Email and role are required.", 1)
+					}
+					workspace, err := getActiveWorkspace(ctx)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					member := findMember(workspace, email)
+					if member == nil {
+						return cli.Exit(fmt.Sprintf("%s is not a member of %q.", email, workspace.Name), 1)
+					}
+					input := &dtos.UpdateWorkspaceMemberInput{Role: strings.ToLower(role)}
+					if _, err := ctx.API.UpdateWorkspaceMember(workspace.ID, member.UserID, input); err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					fmt.Printf("%s is now %s in %q.\n", member.Email, input.Role, workspace.Name)
+					return nil
+				},
+			},
+			{
+				Name:      "remove",
+				Usage:     "Remove a member from the active workspace",
+				ArgsUsage: "<email>",
+				Action: func(c *cli.Context) error {
+					email := strings.TrimSpace(c.Args().First())
+					if email == "" {
+						return cli.Exit("Email is required.", 1)
+					}
+					workspace, err := getActiveWorkspace(ctx)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					member := findMember(workspace, email)
+					if member == nil {
+						return cli.Exit(fmt.Sprintf("%s is not a member of %q.", email, workspace.Name), 1)
+					}
+					if err := ctx.API.RemoveWorkspaceMember(workspace.ID, member.UserID); err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					fmt.Printf("Removed %s from %q.\n", member.Email, workspace.Name)
+					return nil
+				},
+			},
+			{
+				Name:  "audit",
+				Usage: "Show requests in the active workspace that were denied",
+				Action: func(c *cli.Context) error {
+					workspace, err := getActiveWorkspace(ctx)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					events, err := ctx.API.GetWorkspaceAudit(workspace.ID)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					if len(events) == 0 {
+						fmt.Println("No denied requests.")
+						return nil
+					}
+					emails := make(map[string]string)
+					for _, member := range workspace.Members {
+						emails[member.UserID] = member.Email
+					}
+					for _, event := range events {
+						user := emails[event.UserID]
+						if user == "" {
+							user = event.UserID
+						}
+						fmt.Printf("%s  %s  %s (needs %s)\n", event.CreatedAt.Local().Format("2006-01-02 15:04"),
+							user, event.Resource, event.Permission)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "leave",
+				Usage: "Leave the active workspace",
+				Action: func(c *cli.Context) error {
+					workspace, err := getActiveWorkspace(ctx)
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					user, err := ctx.API.GetCurrentUser()
+					if err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					if err := ctx.API.RemoveWorkspaceMember(workspace.ID, user.ID); err != nil {
+						return cli.Exit(err.Error(), 1)
+					}
+					if err := ctx.API.SetActiveWorkspace(nil); err != nil {
+						return cli.Exit("Failed to save active workspace: "+err.Error(), 1)
+					}
+					fmt.Printf("Left %q. Using personal projects.\n", workspace.Name)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func getActiveWorkspace(ctx *app.AppContext) (*models.Workspace, error) {
+	id := ctx.API.GetActiveWorkspaceID()
+	if id == "" {
+		return nil, fmt.Errorf("no workspace is active. Use \"workspace use <name>\" first")
+	}
+	return ctx.API.GetWorkspace(id)
+}
+
+// findWorkspace returns the workspace with the ID, or else the one whose name
+// matches, ignoring case.
+func findWorkspace(workspaces []models.Workspace, query string) (*models.Workspace, error) {
+	var matches []*models.Workspace
+	for i := range workspaces {
+		if workspaces[i].ID == query {
+			return &workspaces[i], nil
+		}
+		if strings.EqualFold(workspaces[i].Name, query) {
+			matches = append(matches, &workspaces[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no workspace named %q", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d workspaces are named %q, use the ID instead", len(matches), query)
+	}
+}
+
+func findMember(workspace *models.Workspace, email string) *models.WorkspaceMember {
+	for i := range workspace.Members {
+		if strings.EqualFold(workspace.Members[i].Email, email) {
+			return &workspace.Members[i]
+		}
+	}
+	return nil
+}
+
+// memberRole returns the role of the member. Members added before roles existed
+// have none and are members, except for the owner.
+func memberRole(workspace *models.Workspace, member *models.WorkspaceMember) string {
+	switch {
+	case member.Role != "":
+		return member.Role
+	case member.UserID == workspace.OwnerID:
+		return models.RoleOwner
+	default:
+		return models.RoleMember
+	}
+}
+
+func activeMarker(active bool) string {
+	if active {
+		return "*"
+	}
+	return " "
+}
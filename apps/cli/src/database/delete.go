@@ -0,0 +1,17 @@
+package database
+
+import (
+	"fmt"
+
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+func (d *DBWrapper) Delete(key string) error {
+	err := d.DB.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete data: %v", err)
+	}
+	return nil
+}
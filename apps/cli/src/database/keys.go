@@ -1,6 +1,13 @@
 package database
 
 var (
-	ServerURLKey = "serverUrl"
-	AuthTokenKey = "authToken"
+	ServerURLKey          = "serverUrl"
+	AuthTokenKey          = "authToken"
+	AuthTokenExpiresAtKey = "authTokenExpiresAt"
+	RefreshTokenKey       = "refreshToken"
+	WorkspaceIDKey        = "workspaceId"
+	WorkspaceNameKey      = "workspaceName"
+
+	PendingOperationKeyPrefix = "pendingOperation:"
+	ProjectCacheKeyPrefix     = "project:"
 )
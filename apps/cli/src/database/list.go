@@ -0,0 +1,30 @@
+package database
+
+import (
+	"fmt"
+
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+// GetAllWithPrefix returns the values of all keys starting with prefix, in key order.
+func (d *DBWrapper) GetAllWithPrefix(prefix string) ([]string, error) {
+	var values []string
+	err := d.DB.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		p := []byte(prefix)
+		for it.Seek(p); it.ValidForPrefix(p); it.Next() {
+			valueCopy, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			values = append(values, string(valueCopy))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list data: %v", err)
+	}
+	return values, nil
+}
@@ -6,7 +6,24 @@ import (
 )
 
 type APIService = apiPkg.APIService
+type PendingOperation = apiPkg.PendingOperation
+type OverlapError = apiPkg.OverlapError
+type TimeEntryFilter = apiPkg.TimeEntryFilter
+
+const (
+	PendingCreate = apiPkg.PendingCreate
+	PendingUpdate = apiPkg.PendingUpdate
+	PendingDelete = apiPkg.PendingDelete
+	TokenEnvVar   = apiPkg.TokenEnvVar
+)
 
 func NewAPIService(db *database.DBWrapper) *APIService {
 	return apiPkg.NewAPIService(db)
 }
+
+var ErrQueuedOffline = apiPkg.ErrQueuedOffline
+var ErrEntryLocked = apiPkg.ErrEntryLocked
+
+func IsUnreachable(err error) bool {
+	return apiPkg.IsUnreachable(err)
+}
@@ -6,12 +6,20 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
 	"TimeTrack-cli/src/database"
 )
 
+// workspaceHeader selects the active workspace on the server.
+const workspaceHeader = "X-Workspace-ID"
+
+// TokenEnvVar names the environment variable with a personal access token. When
+// set it is used instead of the login, e.g. in cron jobs and CI.
+const TokenEnvVar = "TIMETRACK_TOKEN"
+
 type APIService struct {
 	db      *database.DBWrapper
 	baseURL string
@@ -55,8 +63,18 @@ func (api *APIService) GetBaseURL() string {
 	return api.baseURL
 }
 
+// HasAuthToken reports whether requests can be authenticated, either with a
+// login or a personal access token.
+func (api *APIService) HasAuthToken() bool {
+	return os.Getenv(TokenEnvVar) != "" || api.db.Get(database.AuthTokenKey) != ""
+}
+
 func (api *APIService) newAuthRequest(method, url string, body []byte) (*http.Request, error) {
-	token := api.db.Get(database.AuthTokenKey)
+	token := os.Getenv(TokenEnvVar)
+	if token == "" {
+		api.refreshIfExpiring()
+		token = api.db.Get(database.AuthTokenKey)
+	}
 	if token == "" {
 		return nil, fmt.Errorf("no authentication token found")
 	}
@@ -72,6 +90,9 @@ func (api *APIService) newAuthRequest(method, url string, body []byte) (*http.Re
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
+	if workspaceID := api.db.Get(database.WorkspaceIDKey); workspaceID != "" {
+		req.Header.Set(workspaceHeader, workspaceID)
+	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"time"
 
 	"TimeTrack-cli/src/database"
+	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
 )
 
@@ -17,6 +21,13 @@ type AuthPayload struct {
 	Password string `json:"password"`
 }
 
+// refreshMargin is how long before expiry the access token is refreshed.
+const refreshMargin = time.Minute
+
+// ErrSessionExpired is returned when the server no longer accepts the refresh
+// token, e.g. because the session was revoked. The user has to log in again.
+var ErrSessionExpired = errors.New("session expired, please login again")
+
 func (api *APIService) Register(email, password string) error {
 	url := fmt.Sprintf("%s/register", api.baseURL)
 	body, _ := json.Marshal(AuthPayload{Email: email, Password: password})
@@ -39,7 +50,8 @@ func (api *APIService) Register(email, password string) error {
 
 func (api *APIService) Login(email, password string) error {
 	url := fmt.Sprintf("%s/login", api.baseURL)
-	body, _ := json.Marshal(AuthPayload{Email: email, Password: password})
+	deviceName, _ := os.Hostname()
+	body, _ := json.Marshal(dtos.LoginInput{Email: email, Password: password, DeviceName: deviceName})
 
 	resp, err := api.client.Post(url, "application/json", bytes.NewReader(body))
 	if err != nil {
@@ -55,20 +67,175 @@ func (api *APIService) Login(email, password string) error {
 		return fmt.Errorf("login failed: %s", resp.Status)
 	}
 
-	var response struct {
-		Token string `json:"token"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	var tokens models.AuthTokens
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
 		return fmt.Errorf("failed to parse login response: %w", err)
 	}
-	if response.Token == "" {
+	if tokens.Token == "" {
 		return errors.New("login response did not contain a token")
 	}
-	err = api.db.Set(database.AuthTokenKey, response.Token)
+	if err := api.saveTokens(&tokens); err != nil {
+		return fmt.Errorf("failed to save auth token: %w", err)
+	}
+
+	return nil
+}
+
+func (api *APIService) saveTokens(tokens *models.AuthTokens) error {
+	if err := api.db.Set(database.AuthTokenKey, tokens.Token); err != nil {
+		return err
+	}
+	if err := api.db.Set(database.AuthTokenExpiresAtKey, tokens.ExpiresAt.Format(time.RFC3339)); err != nil {
+		return err
+	}
+	if tokens.RefreshToken != "" {
+		return api.db.Set(database.RefreshTokenKey, tokens.RefreshToken)
+	}
+	return nil
+}
+
+func (api *APIService) clearTokens() {
+	for _, key := range []string{database.AuthTokenKey, database.AuthTokenExpiresAtKey, database.RefreshTokenKey} {
+		if err := api.db.Delete(key); err != nil {
+			log.Printf("error deleting %s: %v", key, err)
+		}
+	}
+}
+
+// refreshIfExpiring gets a new access token when the stored one is about to
+// expire. Failures are left to the request that follows, which then fails as
+// unauthorized or unreachable.
+func (api *APIService) refreshIfExpiring() {
+	if api.db.Get(database.RefreshTokenKey) == "" {
+		// Logged in before sessions existed, the token only expires.
+		return
+	}
+	expiresAt, err := time.Parse(time.RFC3339, api.db.Get(database.AuthTokenExpiresAtKey))
+	if err == nil && time.Until(expiresAt) > refreshMargin {
+		return
+	}
+	_ = api.RefreshAuthToken()
+}
+
+// RefreshAuthToken exchanges the refresh token for a new access token. When the
+// session is no longer valid the stored tokens are removed.
+func (api *APIService) RefreshAuthToken() error {
+	refreshToken := api.db.Get(database.RefreshTokenKey)
+	if refreshToken == "" {
+		return ErrSessionExpired
+	}
+
+	url := fmt.Sprintf("%s/token/refresh", api.baseURL)
+	body, _ := json.Marshal(dtos.RefreshTokenInput{RefreshToken: refreshToken})
+
+	resp, err := api.client.Post(url, "application/json", bytes.NewReader(body))
 	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		api.clearTokens()
+		return ErrSessionExpired
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to refresh token: %s", resp.Status)
+	}
+
+	var tokens models.AuthTokens
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return fmt.Errorf("failed to parse refresh response: %w", err)
+	}
+	if err := api.saveTokens(&tokens); err != nil {
 		return fmt.Errorf("failed to save auth token: %w", err)
 	}
+	return nil
+}
+
+// Logout revokes the session on the server and removes the stored tokens. The
+// tokens are removed even when the server cannot be reached.
+func (api *APIService) Logout() error {
+	defer api.clearTokens()
+
+	reqURL := fmt.Sprintf("%s/logout", api.baseURL)
+	req, err := api.newAuthRequest("POST", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
 
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("failed to logout: %s", resp.Status)
+	}
+	return nil
+}
+
+func (api *APIService) GetSessions() ([]models.Session, error) {
+	reqURL := fmt.Sprintf("%s/user/sessions", api.baseURL)
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get sessions: %s", resp.Status)
+	}
+
+	var sessions []models.Session
+	if err := json.NewDecoder(resp.Body).Decode(&sessions); err != nil {
+		return nil, fmt.Errorf("failed to parse sessions response: %w", err)
+	}
+	return sessions, nil
+}
+
+func (api *APIService) RevokeSession(id string) error {
+	reqURL := fmt.Sprintf("%s/user/sessions/%s", api.baseURL, url.PathEscape(id))
+
+	req, err := api.newAuthRequest("DELETE", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.New("session not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to revoke session: %s", resp.Status)
+	}
 	return nil
 }
 
@@ -101,3 +268,38 @@ func (api *APIService) GetCurrentUser() (*models.User, error) {
 
 	return &user, nil
 }
+
+func (api *APIService) UpdateCurrentUser(input *dtos.UpdateUserInput) (*models.User, error) {
+	reqURL := fmt.Sprintf("%s/user", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal user: %w", err)
+	}
+
+	req, err := api.newAuthRequest("PATCH", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to update user: %s", resp.Status)
+	}
+
+	var user models.User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to parse user response: %w", err)
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,41 @@
+package apiService
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"TimeTrack-shared/models"
+)
+
+// GetClients returns the clients of the active workspace, or your personal
+// clients.
+func (api *APIService) GetClients() ([]models.Client, error) {
+	reqURL := fmt.Sprintf("%s/clients", api.baseURL)
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clients: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get clients: %s", decodeAPIError(resp))
+	}
+
+	var clients []models.Client
+	if err := json.NewDecoder(resp.Body).Decode(&clients); err != nil {
+		return nil, fmt.Errorf("failed to parse clients response: %w", err)
+	}
+	return clients, nil
+}
@@ -3,7 +3,13 @@ package apiService
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"time"
+
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
 )
 
 func (api *APIService) GetAtlassianAuthURL() (string, error) {
@@ -30,3 +36,205 @@ func (api *APIService) GetAtlassianAuthURL() (string, error) {
 
 	return oauthURL, nil
 }
+
+// ConnectGitLab stores a GitLab personal access token on the user. The server
+// verifies the token before saving it.
+func (api *APIService) ConnectGitLab(input *dtos.ConnectGitLabInput) error {
+	reqURL := fmt.Sprintf("%s/user/integrations/gitlab", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal GitLab integration: %w", err)
+	}
+
+	req, err := api.newAuthRequest("PUT", reqURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect GitLab: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Details string `json:"details"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Details != "" {
+			return fmt.Errorf("failed to connect GitLab: %s", apiErr.Details)
+		}
+		return fmt.Errorf("failed to connect GitLab: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// SearchJiraIssues returns the Jira issues matching the text query, most
+// recently updated first.
+func (api *APIService) SearchJiraIssues(query string) ([]models.Issue, error) {
+	reqURL := fmt.Sprintf("%s/integrations/jira/search?q=%s", api.baseURL, url.QueryEscape(query))
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Jira issues: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error != "" {
+			return nil, fmt.Errorf("failed to search Jira issues: %s", apiErr.Error)
+		}
+		return nil, fmt.Errorf("failed to search Jira issues: %s", resp.Status)
+	}
+
+	var issues []models.Issue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("failed to parse Jira issues response: %w", err)
+	}
+	return issues, nil
+}
+
+// ReconcileJira compares the Jira worklogs of the user with the reported time
+// entries that started in the range.
+func (api *APIService) ReconcileJira(startDate, endDate string) (*models.ReconcileReport, error) {
+	reqURL := fmt.Sprintf("%s/integrations/jira/reconcile?from=%s&to=%s", api.baseURL, url.QueryEscape(startDate), url.QueryEscape(endDate))
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Reconciliation reads the worklogs of every linked issue, which can be slow.
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile Jira worklogs: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to reconcile Jira worklogs: %s", decodeAPIError(resp))
+	}
+
+	var report models.ReconcileReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse reconcile response: %w", err)
+	}
+	return &report, nil
+}
+
+// ResolveJiraDrift pulls or pushes the given differences between Jira and the
+// time entries.
+func (api *APIService) ResolveJiraDrift(items []dtos.DriftResolution) ([]models.DriftResolutionResult, error) {
+	reqURL := fmt.Sprintf("%s/integrations/jira/reconcile", api.baseURL)
+
+	body, err := json.Marshal(&dtos.ResolveDriftInput{Items: items})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resolutions: %w", err)
+	}
+
+	req, err := api.newAuthRequest("POST", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve differences: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to resolve differences: %s", decodeAPIError(resp))
+	}
+
+	var results []models.DriftResolutionResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to parse resolve response: %w", err)
+	}
+	return results, nil
+}
+
+// decodeAPIError returns the most specific error message of a failed response.
+func decodeAPIError(resp *http.Response) string {
+	var apiErr struct {
+		Error   string `json:"error"`
+		Details string `json:"details"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+	switch {
+	case apiErr.Details != "":
+		return apiErr.Details
+	case apiErr.Error != "":
+		return apiErr.Error
+	default:
+		return resp.Status
+	}
+}
+
+// ImportJiraWorklogs creates time entries for the Jira worklogs of the user in
+// the range, or only reports what would be imported on a dry run.
+func (api *APIService) ImportJiraWorklogs(input *dtos.ImportWorklogsInput) (*models.WorklogImportResult, error) {
+	reqURL := fmt.Sprintf("%s/integrations/jira/import", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal import: %w", err)
+	}
+
+	req, err := api.newAuthRequest("POST", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Months of worklogs are read from Jira, which can take a while.
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import Jira worklogs: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to import Jira worklogs: %s", decodeAPIError(resp))
+	}
+
+	var result models.WorklogImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse import response: %w", err)
+	}
+	return &result, nil
+}
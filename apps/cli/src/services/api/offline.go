@@ -0,0 +1,255 @@
+package apiService
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"TimeTrack-cli/src/database"
+	"TimeTrack-shared/dtos"
+)
+
+// ErrQueuedOffline is returned when a change could not reach the server and was
+// stored locally instead. It is replayed by SyncPendingOperations.
+var ErrQueuedOffline = errors.New("server unreachable, change saved locally and will be synced later")
+
+const (
+	PendingCreate = "create"
+	PendingUpdate = "update"
+	PendingDelete = "delete"
+
+	pendingIDPrefix = "pending-"
+)
+
+type PendingOperation struct {
+	ID            string                     `json:"id"`
+	Type          string                     `json:"type"`
+	EntryID       string                     `json:"entry_id,omitempty"`
+	Create        *dtos.CreateTimeEntryInput `json:"create,omitempty"`
+	Update        *dtos.UpdateTimeEntryInput `json:"update,omitempty"`
+	BaseUpdatedAt time.Time                  `json:"base_updated_at"` // server version the change was made against
+	Conflict      string                     `json:"conflict,omitempty"`
+	QueuedAt      time.Time                  `json:"queued_at"`
+}
+
+// EntryRef returns the ID under which the affected entry is known locally.
+func (op PendingOperation) EntryRef() string {
+	if op.Type == PendingCreate {
+		return pendingIDPrefix + op.ID
+	}
+	return op.EntryID
+}
+
+type SyncResult struct {
+	Synced    int
+	Conflicts []PendingOperation
+}
+
+// IsUnreachable reports whether err was caused by the server not answering,
+// as opposed to the server rejecting the request.
+func IsUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// IsPendingID reports whether id refers to an entry that only exists locally.
+func IsPendingID(id string) bool {
+	return strings.HasPrefix(id, pendingIDPrefix)
+}
+
+func (api *APIService) GetPendingOperations() ([]PendingOperation, error) {
+	values, err := api.db.GetAllWithPrefix(database.PendingOperationKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]PendingOperation, 0, len(values))
+	for _, v := range values {
+		var op PendingOperation
+		if err := json.Unmarshal([]byte(v), &op); err != nil {
+			return nil, fmt.Errorf("failed to parse pending operation: %w", err)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func (api *APIService) DiscardPendingOperation(id string) error {
+	return api.db.Delete(database.PendingOperationKeyPrefix + id)
+}
+
+func (api *APIService) savePendingOperation(op *PendingOperation) error {
+	value, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending operation: %w", err)
+	}
+	return api.db.Set(database.PendingOperationKeyPrefix+op.ID, string(value))
+}
+
+// queueOperation stores op for later replay. Changes to entries that were
+// themselves created offline are folded into the pending create.
+func (api *APIService) queueOperation(op *PendingOperation) error {
+	if IsPendingID(op.EntryID) {
+		createID := strings.TrimPrefix(op.EntryID, pendingIDPrefix)
+		ops, err := api.GetPendingOperations()
+		if err != nil {
+			return err
+		}
+		for _, pending := range ops {
+			if pending.ID != createID || pending.Type != PendingCreate {
+				continue
+			}
+			if op.Type == PendingDelete {
+				return api.DiscardPendingOperation(createID)
+			}
+			applyUpdateToCreate(pending.Create, op.Update)
+			return api.savePendingOperation(&pending)
+		}
+		return fmt.Errorf("pending time entry not found: %s", op.EntryID)
+	}
+
+	// Zero padded so badger key order matches queue order.
+	op.ID = fmt.Sprintf("%020d", time.Now().UnixNano())
+	op.QueuedAt = time.Now()
+	return api.savePendingOperation(op)
+}
+
+func applyUpdateToCreate(create *dtos.CreateTimeEntryInput, update *dtos.UpdateTimeEntryInput) {
+	if update == nil {
+		return
+	}
+	if update.ProjectID != nil {
+		create.ProjectID = *update.ProjectID
+	}
+	if update.Period != nil {
+		create.Period = *update.Period
+	}
+	if update.Note != nil {
+		create.Note = *update.Note
+	}
+	if update.Billable != nil {
+		create.Billable = update.Billable
+	}
+	if update.Tags != nil {
+		create.Tags = *update.Tags
+	}
+}
+
+// replayedVersion records that a change made against the version From of an
+// entry was replayed and produced the version To.
+type replayedVersion struct {
+	From time.Time
+	To   time.Time
+}
+
+// SyncPendingOperations replays queued changes in the order they were made.
+// Changes that conflict with the server are flagged and kept for the user to
+// resolve. Replay stops when the server becomes unreachable again.
+func (api *APIService) SyncPendingOperations() (*SyncResult, error) {
+	ops, err := api.GetPendingOperations()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	replayed := make(map[string]replayedVersion)
+	for _, op := range ops {
+		if op.Conflict != "" {
+			result.Conflicts = append(result.Conflicts, op)
+			continue
+		}
+
+		// A later change of an entry was made against the same version as the
+		// change replayed before it, so it is moved onto the version the
+		// replay produced instead of conflicting with it.
+		if version, ok := replayed[op.EntryID]; ok && !op.BaseUpdatedAt.IsZero() && !op.BaseUpdatedAt.After(version.From) {
+			op.BaseUpdatedAt = version.To
+			if err := api.savePendingOperation(&op); err != nil {
+				return result, err
+			}
+		}
+
+		conflict, err := api.replayOperation(&op)
+		if err != nil {
+			if IsUnreachable(err) {
+				return result, err
+			}
+			conflict = err.Error()
+		}
+		if conflict != "" {
+			op.Conflict = conflict
+			if err := api.savePendingOperation(&op); err != nil {
+				return result, err
+			}
+			result.Conflicts = append(result.Conflicts, op)
+			continue
+		}
+
+		if err := api.DiscardPendingOperation(op.ID); err != nil {
+			return result, err
+		}
+		result.Synced++
+
+		if op.Type == PendingUpdate {
+			current, err := api.GetTimeEntry(op.EntryID)
+			if err != nil {
+				return result, err
+			}
+			if current != nil {
+				replayed[op.EntryID] = replayedVersion{From: op.BaseUpdatedAt, To: current.UpdatedAt}
+			}
+		}
+	}
+	return result, nil
+}
+
+// replayOperation sends op to the server. A non-empty string describes why op
+// conflicts with the current server state and was not applied.
+func (api *APIService) replayOperation(op *PendingOperation) (string, error) {
+	switch op.Type {
+	case PendingCreate:
+		_, err := api.createTimeEntry(op.Create)
+		return rejectionConflict(err)
+	case PendingUpdate:
+		current, err := api.GetTimeEntry(op.EntryID)
+		if err != nil {
+			return "", err
+		}
+		if current == nil {
+			return "time entry was deleted on the server", nil
+		}
+		if current.UpdatedAt.After(op.BaseUpdatedAt) {
+			return "time entry was changed on the server", nil
+		}
+		return rejectionConflict(api.updateTimeEntry(op.EntryID, op.Update))
+	case PendingDelete:
+		current, err := api.GetTimeEntry(op.EntryID)
+		if err != nil {
+			return "", err
+		}
+		if current == nil {
+			return "", nil
+		}
+		if !op.BaseUpdatedAt.IsZero() && current.UpdatedAt.After(op.BaseUpdatedAt) {
+			return "time entry was changed on the server", nil
+		}
+		return rejectionConflict(api.deleteTimeEntry(op.EntryID))
+	}
+	return fmt.Sprintf("unknown operation: %s", op.Type), nil
+}
+
+// rejectionConflict turns a rejection because of an overlap or a locked
+// timesheet into a conflict message, so the operation is kept for the user to
+// resolve instead of being retried.
+func rejectionConflict(err error) (string, error) {
+	var overlapErr *OverlapError
+	if errors.As(err, &overlapErr) {
+		return overlapErr.Error(), nil
+	}
+	if errors.Is(err, ErrEntryLocked) {
+		return err.Error(), nil
+	}
+	return "", err
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strings"
 
+	"TimeTrack-cli/src/database"
 	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
 )
@@ -22,6 +23,9 @@ func (api *APIService) GetProjectByName(name string) (*models.Project, error) {
 
 	resp, err := api.client.Do(req)
 	if err != nil {
+		if cached := api.getCachedProject(name); cached != nil {
+			return cached, nil
+		}
 		return nil, fmt.Errorf("failed to get project by name: %w", err)
 	}
 	defer func() {
@@ -34,18 +38,53 @@ func (api *APIService) GetProjectByName(name string) (*models.Project, error) {
 		return nil, fmt.Errorf("failed to get project by name: %s", resp.Status)
 	}
 
-	var projects []models.Project
-	if err := json.NewDecoder(resp.Body).Decode(&projects); err != nil {
+	var page models.ProjectPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return nil, fmt.Errorf("failed to parse projects response: %w", err)
 	}
-	if len(projects) == 0 {
+	if len(page.Projects) == 0 {
 		return nil, fmt.Errorf("no project found with name: %s", name)
 	}
-	return &projects[0], nil
+	api.cacheProject(name, &page.Projects[0])
+	return &page.Projects[0], nil
+}
+
+// cacheProject remembers the project resolved for name so that entries can be
+// logged against it while the server is unreachable.
+func (api *APIService) cacheProject(name string, project *models.Project) {
+	value, err := json.Marshal(project)
+	if err != nil {
+		return
+	}
+	if err := api.db.Set(api.projectCacheKey(name), string(value)); err != nil {
+		log.Printf("failed to cache project: %v", err)
+	}
+}
+
+// projectCacheKey keeps projects of different workspaces with the same name
+// apart.
+func (api *APIService) projectCacheKey(name string) string {
+	if workspaceID := api.db.Get(database.WorkspaceIDKey); workspaceID != "" {
+		return database.ProjectCacheKeyPrefix + workspaceID + ":" + name
+	}
+	return database.ProjectCacheKeyPrefix + name
+}
+
+func (api *APIService) getCachedProject(name string) *models.Project {
+	value := api.db.Get(api.projectCacheKey(name))
+	if value == "" {
+		return nil
+	}
+	var project models.Project
+	if err := json.Unmarshal([]byte(value), &project); err != nil {
+		return nil
+	}
+	return &project
 }
 
 func (api *APIService) GetProjectByIds(ids []string) ([]models.Project, error) {
-	reqURL := fmt.Sprintf("%s/projects?ids=%s", api.baseURL, url.QueryEscape(strings.Join(ids, ",")))
+	// All of the projects fit on one page.
+	reqURL := fmt.Sprintf("%s/projects?ids=%s&limit=%d", api.baseURL, url.QueryEscape(strings.Join(ids, ",")), len(ids))
 
 	req, err := api.newAuthRequest("GET", reqURL, nil)
 	if err != nil {
@@ -66,11 +105,11 @@ func (api *APIService) GetProjectByIds(ids []string) ([]models.Project, error) {
 		return nil, fmt.Errorf("failed to get projects by IDs: %s", resp.Status)
 	}
 
-	var projects []models.Project
-	if err := json.NewDecoder(resp.Body).Decode(&projects); err != nil {
+	var page models.ProjectPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return nil, fmt.Errorf("failed to parse projects response: %w", err)
 	}
-	return projects, nil
+	return page.Projects, nil
 }
 
 func (api *APIService) CreateProject(project *dtos.CreateProjectInput) (*models.Project, error) {
@@ -104,6 +143,7 @@ func (api *APIService) CreateProject(project *dtos.CreateProjectInput) (*models.
 	if err := json.NewDecoder(resp.Body).Decode(&createdProject); err != nil {
 		return nil, fmt.Errorf("failed to parse created project response: %w", err)
 	}
+	api.cacheProject(createdProject.Name, &createdProject)
 
 	return &createdProject, nil
 }
@@ -0,0 +1,50 @@
+package apiService
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DownloadMonthlyReport writes the timesheet of the month, e.g. "2026-09", as
+// a PDF or HTML file to w. A client ID limits it to the projects of the client,
+// and user selects a member of the active workspace, or "all".
+func (api *APIService) DownloadMonthlyReport(month, format, clientID, user string, w io.Writer) error {
+	query := url.Values{"month": {month}, "format": {format}}
+	if clientID != "" {
+		query.Set("client", clientID)
+	}
+	if user != "" {
+		query.Set("user", user)
+	}
+	reqURL := fmt.Sprintf("%s/reports/monthly?%s", api.baseURL, query.Encode())
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// A month of entries takes longer to render than regular requests.
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get report: %s", decodeAPIError(resp))
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package apiService
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+)
+
+func (api *APIService) GetUserSettings() (*models.UserSettings, error) {
+	reqURL := fmt.Sprintf("%s/user/settings", api.baseURL)
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get settings: %s", resp.Status)
+	}
+
+	var settings models.UserSettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings response: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (api *APIService) UpdateUserSettings(input *dtos.UpdateUserSettingsInput) (*models.UserSettings, error) {
+	reqURL := fmt.Sprintf("%s/user/settings", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	req, err := api.newAuthRequest("PATCH", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update settings: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to update settings: %s", resp.Status)
+	}
+
+	var settings models.UserSettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings response: %w", err)
+	}
+
+	return &settings, nil
+}
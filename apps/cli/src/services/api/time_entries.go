@@ -2,15 +2,57 @@ package apiService
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"TimeTrack-shared/dtos"
 	"TimeTrack-shared/models"
 )
 
+// OverlapError is returned when the server rejects an entry because it overlaps
+// other entries of the user.
+type OverlapError struct {
+	Conflicts []models.TimeEntry
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("time entry overlaps %d existing entries", len(e.Conflicts))
+}
+
+// ErrEntryLocked is returned when the server refuses a change to an entry in a
+// week whose timesheet is submitted or approved.
+var ErrEntryLocked = errors.New("time entry is in a submitted or approved timesheet")
+
+func decodeOverlapError(resp *http.Response) error {
+	var body struct {
+		Conflicts []models.TimeEntry `json:"conflicts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to parse conflict response: %w", err)
+	}
+	return &OverlapError{Conflicts: body.Conflicts}
+}
+
+// CreateTimeEntry creates the entry on the server. When the server cannot be
+// reached the entry is queued locally and ErrQueuedOffline is returned.
 func (api *APIService) CreateTimeEntry(entry *dtos.CreateTimeEntryInput) (*models.TimeEntry, error) {
+	created, err := api.createTimeEntry(entry)
+	if err != nil && IsUnreachable(err) {
+		if qerr := api.queueOperation(&PendingOperation{Type: PendingCreate, Create: entry}); qerr != nil {
+			return nil, fmt.Errorf("failed to queue time entry: %w", qerr)
+		}
+		return nil, ErrQueuedOffline
+	}
+	return created, err
+}
+
+func (api *APIService) createTimeEntry(entry *dtos.CreateTimeEntryInput) (*models.TimeEntry, error) {
 	reqURL := fmt.Sprintf("%s/time-entries", api.baseURL)
 
 	body, err := json.Marshal(entry)
@@ -33,6 +75,12 @@ func (api *APIService) CreateTimeEntry(entry *dtos.CreateTimeEntryInput) (*model
 		}
 	}()
 
+	if resp.StatusCode == http.StatusLocked {
+		return nil, ErrEntryLocked
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, decodeOverlapError(resp)
+	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, fmt.Errorf("failed to create time entry: %s", resp.Status)
 	}
@@ -45,11 +93,62 @@ func (api *APIService) CreateTimeEntry(entry *dtos.CreateTimeEntryInput) (*model
 	return &createdEntry, nil
 }
 
-func (api *APIService) GetTimeEntries(startDate, endDate string, page int) ([]*models.TimeEntry, error) {
-	limit := 25
-	skip := (page - 1) * limit
+// TimeEntryFilter narrows the entries returned by GetTimeEntries. Fields left
+// at their zero value do not filter.
+type TimeEntryFilter struct {
+	ProjectIDs  []string
+	Text        string // words of the note
+	Reported    *bool
+	Integration string // e.g. "jira"
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	Tags        []string // entries must have all of them
+}
 
-	reqURL := fmt.Sprintf("%s/time-entries?from=%s&to=%s&skip=%d&limit=%d", api.baseURL, startDate, endDate, skip, limit)
+// IsZero reports whether the filter matches every entry.
+func (f TimeEntryFilter) IsZero() bool {
+	return len(f.ProjectIDs) == 0 && f.Text == "" && f.Reported == nil && f.Integration == "" &&
+		f.MinDuration == 0 && f.MaxDuration == 0 && len(f.Tags) == 0
+}
+
+func (f TimeEntryFilter) addTo(query url.Values) {
+	for _, id := range f.ProjectIDs {
+		query.Add("project", id)
+	}
+	if f.Text != "" {
+		query.Set("q", f.Text)
+	}
+	if f.Reported != nil {
+		query.Set("reported", strconv.FormatBool(*f.Reported))
+	}
+	if f.Integration != "" {
+		query.Set("integration", f.Integration)
+	}
+	if f.MinDuration > 0 {
+		query.Set("min_duration", strconv.Itoa(int(f.MinDuration.Seconds())))
+	}
+	if f.MaxDuration > 0 {
+		query.Set("max_duration", strconv.Itoa(int(f.MaxDuration.Seconds())))
+	}
+	for _, tag := range f.Tags {
+		query.Add("tag", tag)
+	}
+}
+
+// GetTimeEntries returns a page of up to limit entries, newest first. The first
+// page is fetched with an empty cursor, the next one with the NextCursor of the
+// page before.
+func (api *APIService) GetTimeEntries(startDate, endDate string, cursor string, limit int, filter TimeEntryFilter) (*models.TimeEntryPage, error) {
+	query := url.Values{
+		"from":  {startDate},
+		"to":    {endDate},
+		"limit": {strconv.Itoa(limit)},
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	filter.addTo(query)
+	reqURL := fmt.Sprintf("%s/time-entries?%s", api.baseURL, query.Encode())
 
 	req, err := api.newAuthRequest("GET", reqURL, nil)
 	if err != nil {
@@ -70,16 +169,20 @@ func (api *APIService) GetTimeEntries(startDate, endDate string, page int) ([]*m
 		return nil, fmt.Errorf("failed to get time entries: %s", resp.Status)
 	}
 
-	var entries []*models.TimeEntry
-	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+	var page models.TimeEntryPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return nil, fmt.Errorf("failed to parse time entries response: %w", err)
 	}
 
-	return entries, nil
+	return &page, nil
 }
 
 func (api *APIService) GetTimeEntryStatistics(startDate, endDate string) (*models.TimeEntryStatistics, error) {
-	reqURL := fmt.Sprintf("%s/time-entries/statistics?from=%s&to=%s", api.baseURL, startDate, endDate)
+	query := url.Values{
+		"from": {startDate},
+		"to":   {endDate},
+	}
+	reqURL := fmt.Sprintf("%s/time-entries/statistics?%s", api.baseURL, query.Encode())
 
 	req, err := api.newAuthRequest("GET", reqURL, nil)
 	if err != nil {
@@ -108,7 +211,115 @@ func (api *APIService) GetTimeEntryStatistics(startDate, endDate string) (*model
 	return stats, nil
 }
 
+// UpdateTimeEntry updates entry on the server. When the server cannot be reached
+// the change is queued locally and ErrQueuedOffline is returned. Entries that
+// only exist locally are updated in the queue.
+func (api *APIService) UpdateTimeEntry(entry *models.TimeEntry, input *dtos.UpdateTimeEntryInput) error {
+	op := &PendingOperation{Type: PendingUpdate, EntryID: entry.ID, Update: input, BaseUpdatedAt: entry.UpdatedAt}
+	if IsPendingID(entry.ID) {
+		return api.queueOperation(op)
+	}
+
+	err := api.updateTimeEntry(entry.ID, input)
+	if err != nil && IsUnreachable(err) {
+		if qerr := api.queueOperation(op); qerr != nil {
+			return fmt.Errorf("failed to queue time entry update: %w", qerr)
+		}
+		return ErrQueuedOffline
+	}
+	return err
+}
+
+func (api *APIService) updateTimeEntry(timeEntryId string, input *dtos.UpdateTimeEntryInput) error {
+	reqURL := fmt.Sprintf("%s/time-entries/%s", api.baseURL, timeEntryId)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal time entry: %w", err)
+	}
+
+	req, err := api.newAuthRequest("PUT", reqURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update time entry: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusLocked {
+		return ErrEntryLocked
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return decodeOverlapError(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to update time entry: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// GetTimeEntry returns the entry with the given ID, or nil when it does not exist.
+func (api *APIService) GetTimeEntry(timeEntryId string) (*models.TimeEntry, error) {
+	reqURL := fmt.Sprintf("%s/time-entries/%s", api.baseURL, timeEntryId)
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get time entry: %s", resp.Status)
+	}
+
+	var entry models.TimeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("failed to parse time entry response: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// DeleteTimeEntry deletes the entry on the server. When the server cannot be
+// reached the deletion is queued locally and ErrQueuedOffline is returned.
+// Entries that only exist locally are removed from the queue.
 func (api *APIService) DeleteTimeEntry(timeEntryId string) error {
+	op := &PendingOperation{Type: PendingDelete, EntryID: timeEntryId}
+	if IsPendingID(timeEntryId) {
+		return api.queueOperation(op)
+	}
+
+	err := api.deleteTimeEntry(timeEntryId)
+	if err != nil && IsUnreachable(err) {
+		if qerr := api.queueOperation(op); qerr != nil {
+			return fmt.Errorf("failed to queue time entry deletion: %w", qerr)
+		}
+		return ErrQueuedOffline
+	}
+	return err
+}
+
+func (api *APIService) deleteTimeEntry(timeEntryId string) error {
 	reqURL := fmt.Sprintf("%s/time-entries/%s", api.baseURL, timeEntryId)
 
 	req, err := api.newAuthRequest("DELETE", reqURL, nil)
@@ -126,9 +337,238 @@ func (api *APIService) DeleteTimeEntry(timeEntryId string) error {
 		}
 	}()
 
+	if resp.StatusCode == http.StatusLocked {
+		return ErrEntryLocked
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("failed to delete time entry: %s", resp.Status)
 	}
 
 	return nil
 }
+
+func (api *APIService) StartTimer(input *dtos.StartTimerInput) (*models.TimeEntry, error) {
+	reqURL := fmt.Sprintf("%s/time-entries/timer/start", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal timer: %w", err)
+	}
+
+	req, err := api.newAuthRequest("POST", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusLocked {
+		return nil, ErrEntryLocked
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, fmt.Errorf("a timer is already running")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to start timer: %s", resp.Status)
+	}
+
+	var entry models.TimeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("failed to parse timer response: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (api *APIService) StopTimer(input *dtos.StopTimerInput) (*models.TimeEntry, error) {
+	reqURL := fmt.Sprintf("%s/time-entries/timer/stop", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal timer: %w", err)
+	}
+
+	req, err := api.newAuthRequest("POST", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusLocked {
+		return nil, ErrEntryLocked
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, decodeOverlapError(resp)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no timer is running")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to stop timer: %s", resp.Status)
+	}
+
+	var entry models.TimeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("failed to parse timer response: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// GetRunningTimer returns the running time entry, or nil when no timer is running.
+func (api *APIService) GetRunningTimer() (*models.TimeEntry, error) {
+	reqURL := fmt.Sprintf("%s/time-entries/timer", api.baseURL)
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timer: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get timer: %s", resp.Status)
+	}
+
+	var entry models.TimeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("failed to parse timer response: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (api *APIService) ReportTimeEntries(ids []string) ([]models.TimeEntryReportResult, error) {
+	reqURL := fmt.Sprintf("%s/time-entries/report", api.baseURL)
+
+	body, err := json.Marshal(dtos.ReportTimeEntriesInput{IDs: ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report request: %w", err)
+	}
+
+	req, err := api.newAuthRequest("POST", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to report time entries: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to report time entries: %s", resp.Status)
+	}
+
+	var results []models.TimeEntryReportResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to parse report response: %w", err)
+	}
+
+	return results, nil
+}
+
+// BulkCreateTimeEntries sends many entries at once. The server validates every
+// row and inserts nothing if any row is invalid, in which case the returned
+// result holds the row-level errors.
+func (api *APIService) BulkCreateTimeEntries(input *dtos.BulkCreateTimeEntriesInput) (*models.BulkTimeEntriesResult, error) {
+	reqURL := fmt.Sprintf("%s/time-entries/bulk", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal time entries: %w", err)
+	}
+
+	req, err := api.newAuthRequest("POST", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Validating and inserting thousands of rows takes longer than regular requests.
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import time entries: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnprocessableEntity {
+		return nil, fmt.Errorf("failed to import time entries: %s", resp.Status)
+	}
+
+	var result models.BulkTimeEntriesResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse import response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ExportTimeEntries downloads all entries in the range in the given format and
+// writes them to w.
+func (api *APIService) ExportTimeEntries(format, startDate, endDate string, w io.Writer) error {
+	reqURL := fmt.Sprintf("%s/time-entries/export?format=%s&from=%s&to=%s", api.baseURL, url.QueryEscape(format), url.QueryEscape(startDate), url.QueryEscape(endDate))
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Exports can be large, so allow more time than regular requests.
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export time entries: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to export time entries: %s", resp.Status)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download export: %w", err)
+	}
+	return nil
+}
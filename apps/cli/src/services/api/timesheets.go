@@ -0,0 +1,135 @@
+package apiService
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+)
+
+// SubmitTimesheet submits the ISO week, such as "2026-W38", of the active
+// workspace for approval.
+func (api *APIService) SubmitTimesheet(week string) (*models.Timesheet, error) {
+	reqURL := fmt.Sprintf("%s/timesheets", api.baseURL)
+	return api.sendTimesheetRequest("POST", reqURL, &dtos.SubmitTimesheetInput{Week: week}, "submit timesheet")
+}
+
+// GetTimesheets returns your timesheets in the active workspace.
+func (api *APIService) GetTimesheets() ([]models.Timesheet, error) {
+	return api.getTimesheetList(fmt.Sprintf("%s/timesheets", api.baseURL))
+}
+
+// GetTimesheetsForReview returns the timesheets of the members of the active
+// workspace that wait for review.
+func (api *APIService) GetTimesheetsForReview() ([]models.Timesheet, error) {
+	return api.getTimesheetList(fmt.Sprintf("%s/timesheets/review", api.baseURL))
+}
+
+func (api *APIService) GetTimesheet(id string) (*models.Timesheet, error) {
+	reqURL := fmt.Sprintf("%s/timesheets/%s", api.baseURL, url.PathEscape(id))
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timesheet: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New("timesheet not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get timesheet: %s", decodeAPIError(resp))
+	}
+
+	var timesheet models.Timesheet
+	if err := json.NewDecoder(resp.Body).Decode(&timesheet); err != nil {
+		return nil, fmt.Errorf("failed to parse timesheet response: %w", err)
+	}
+	return &timesheet, nil
+}
+
+func (api *APIService) ApproveTimesheet(id string, comment string) (*models.Timesheet, error) {
+	reqURL := fmt.Sprintf("%s/timesheets/%s/approve", api.baseURL, url.PathEscape(id))
+	return api.sendTimesheetRequest("POST", reqURL, &dtos.ReviewTimesheetInput{Comment: comment}, "approve timesheet")
+}
+
+func (api *APIService) RejectTimesheet(id string, comment string) (*models.Timesheet, error) {
+	reqURL := fmt.Sprintf("%s/timesheets/%s/reject", api.baseURL, url.PathEscape(id))
+	return api.sendTimesheetRequest("POST", reqURL, &dtos.ReviewTimesheetInput{Comment: comment}, "reject timesheet")
+}
+
+func (api *APIService) getTimesheetList(reqURL string) ([]models.Timesheet, error) {
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timesheets: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get timesheets: %s", decodeAPIError(resp))
+	}
+
+	var timesheets []models.Timesheet
+	if err := json.NewDecoder(resp.Body).Decode(&timesheets); err != nil {
+		return nil, fmt.Errorf("failed to parse timesheets response: %w", err)
+	}
+	return timesheets, nil
+}
+
+func (api *APIService) sendTimesheetRequest(method, reqURL string, input interface{}, action string) (*models.Timesheet, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	req, err := api.newAuthRequest(method, reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New("timesheet not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to %s: %s", action, decodeAPIError(resp))
+	}
+
+	var timesheet models.Timesheet
+	if err := json.NewDecoder(resp.Body).Decode(&timesheet); err != nil {
+		return nil, fmt.Errorf("failed to parse timesheet response: %w", err)
+	}
+	return &timesheet, nil
+}
@@ -0,0 +1,103 @@
+package apiService
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+)
+
+func (api *APIService) GetAPITokens() ([]models.APIToken, error) {
+	reqURL := fmt.Sprintf("%s/user/tokens", api.baseURL)
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get tokens: %s", resp.Status)
+	}
+
+	var tokens []models.APIToken
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse tokens response: %w", err)
+	}
+	return tokens, nil
+}
+
+func (api *APIService) CreateAPIToken(input *dtos.CreateAPITokenInput) (*models.CreatedAPIToken, error) {
+	reqURL := fmt.Sprintf("%s/user/tokens", api.baseURL)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	req, err := api.newAuthRequest("POST", reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create token: %s", decodeAPIError(resp))
+	}
+
+	var token models.CreatedAPIToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	return &token, nil
+}
+
+func (api *APIService) RevokeAPIToken(id string) error {
+	reqURL := fmt.Sprintf("%s/user/tokens/%s", api.baseURL, url.PathEscape(id))
+
+	req, err := api.newAuthRequest("DELETE", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.New("token not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to revoke token: %s", resp.Status)
+	}
+	return nil
+}
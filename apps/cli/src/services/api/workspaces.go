@@ -0,0 +1,198 @@
+package apiService
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"TimeTrack-cli/src/database"
+	"TimeTrack-shared/dtos"
+	"TimeTrack-shared/models"
+)
+
+// SetActiveWorkspace makes projects be looked up and created in the workspace.
+// A nil workspace switches back to personal projects.
+func (api *APIService) SetActiveWorkspace(workspace *models.Workspace) error {
+	if workspace == nil {
+		if err := api.db.Delete(database.WorkspaceIDKey); err != nil {
+			return err
+		}
+		return api.db.Delete(database.WorkspaceNameKey)
+	}
+	if err := api.db.Set(database.WorkspaceIDKey, workspace.ID); err != nil {
+		return err
+	}
+	return api.db.Set(database.WorkspaceNameKey, workspace.Name)
+}
+
+// GetActiveWorkspaceID returns the ID of the active workspace, or an empty
+// string when personal projects are used.
+func (api *APIService) GetActiveWorkspaceID() string {
+	return api.db.Get(database.WorkspaceIDKey)
+}
+
+func (api *APIService) GetWorkspaces() ([]models.Workspace, error) {
+	reqURL := fmt.Sprintf("%s/workspaces", api.baseURL)
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get workspaces: %s", decodeAPIError(resp))
+	}
+
+	var workspaces []models.Workspace
+	if err := json.NewDecoder(resp.Body).Decode(&workspaces); err != nil {
+		return nil, fmt.Errorf("failed to parse workspaces response: %w", err)
+	}
+	return workspaces, nil
+}
+
+func (api *APIService) GetWorkspace(id string) (*models.Workspace, error) {
+	reqURL := fmt.Sprintf("%s/workspaces/%s", api.baseURL, url.PathEscape(id))
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New("workspace not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get workspace: %s", decodeAPIError(resp))
+	}
+
+	var workspace models.Workspace
+	if err := json.NewDecoder(resp.Body).Decode(&workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace response: %w", err)
+	}
+	return &workspace, nil
+}
+
+func (api *APIService) CreateWorkspace(input *dtos.CreateWorkspaceInput) (*models.Workspace, error) {
+	reqURL := fmt.Sprintf("%s/workspaces", api.baseURL)
+	return api.sendWorkspaceRequest("POST", reqURL, input, "create workspace")
+}
+
+func (api *APIService) AddWorkspaceMember(id string, input *dtos.AddWorkspaceMemberInput) (*models.Workspace, error) {
+	reqURL := fmt.Sprintf("%s/workspaces/%s/members", api.baseURL, url.PathEscape(id))
+	return api.sendWorkspaceRequest("POST", reqURL, input, "add member")
+}
+
+func (api *APIService) UpdateWorkspaceMember(id string, userID string, input *dtos.UpdateWorkspaceMemberInput) (*models.Workspace, error) {
+	reqURL := fmt.Sprintf("%s/workspaces/%s/members/%s", api.baseURL, url.PathEscape(id), url.PathEscape(userID))
+	return api.sendWorkspaceRequest("PATCH", reqURL, input, "update member")
+}
+
+// GetWorkspaceAudit returns the latest requests in the workspace that were
+// denied by the permission checks.
+func (api *APIService) GetWorkspaceAudit(id string) ([]models.AuditEvent, error) {
+	reqURL := fmt.Sprintf("%s/workspaces/%s/audit", api.baseURL, url.PathEscape(id))
+
+	req, err := api.newAuthRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get audit log: %s", decodeAPIError(resp))
+	}
+
+	var events []models.AuditEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("failed to parse audit log response: %w", err)
+	}
+	return events, nil
+}
+
+func (api *APIService) RemoveWorkspaceMember(id string, userID string) error {
+	reqURL := fmt.Sprintf("%s/workspaces/%s/members/%s", api.baseURL, url.PathEscape(id), url.PathEscape(userID))
+
+	req, err := api.newAuthRequest("DELETE", reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to remove member: %s", decodeAPIError(resp))
+	}
+	return nil
+}
+
+func (api *APIService) sendWorkspaceRequest(method, reqURL string, input interface{}, action string) (*models.Workspace, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	req, err := api.newAuthRequest(method, reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to %s: %s", action, decodeAPIError(resp))
+	}
+
+	var workspace models.Workspace
+	if err := json.NewDecoder(resp.Body).Decode(&workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace response: %w", err)
+	}
+	return &workspace, nil
+}
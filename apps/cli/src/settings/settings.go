@@ -5,7 +5,9 @@ import (
 	"TimeTrack-cli/src/database"
 	"TimeTrack-cli/src/services"
 	"TimeTrack-cli/src/utils"
+	"TimeTrack-shared/dtos"
 	"fmt"
+	"strconv"
 )
 
 type Setting interface {
@@ -66,6 +68,50 @@ func (s StaticSetting) Action() func() (string, error) {
 	return nil
 }
 
+// ToggleSetting is an on/off setting stored on the server. Its action flips
+// the current value.
+type ToggleSetting struct {
+	id       string
+	label    string
+	category string
+	getter   func() (bool, error)
+	setter   func(bool) error
+}
+
+func (s ToggleSetting) ID() string       { return s.id }
+func (s ToggleSetting) Label() string    { return s.label }
+func (s ToggleSetting) Type() string     { return "toggle" }
+func (s ToggleSetting) Category() string { return s.category }
+func (s ToggleSetting) Get() string {
+	enabled, err := s.getter()
+	if err != nil {
+		return "Unavailable - " + err.Error()
+	}
+	if enabled {
+		return "Enabled"
+	}
+	return "Disabled"
+}
+func (s ToggleSetting) Set(v string) error {
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid value %q, expected true or false", v)
+	}
+	return s.setter(enabled)
+}
+func (s ToggleSetting) Action() func() (string, error) {
+	return func() (string, error) {
+		enabled, err := s.getter()
+		if err != nil {
+			return "", err
+		}
+		if err := s.setter(!enabled); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s is now %s.", s.label, s.Get()), nil
+	}
+}
+
 func GetAllSettings(db *database.DBWrapper) []Setting {
 	api := services.NewAPIService(db)
 
@@ -102,6 +148,38 @@ func GetAllSettings(db *database.DBWrapper) []Setting {
 			key:      database.ServerURLKey,
 			def:      config.DefaultServerURL,
 		},
+		ToggleSetting{
+			id:       "auto_link_jira",
+			label:    "Auto-link to Jira",
+			category: "Projects",
+			getter: func() (bool, error) {
+				settings, err := api.GetUserSettings()
+				if err != nil {
+					return false, err
+				}
+				return settings.AutoLinkJira, nil
+			},
+			setter: func(enabled bool) error {
+				_, err := api.UpdateUserSettings(&dtos.UpdateUserSettingsInput{AutoLinkJira: &enabled})
+				return err
+			},
+		},
+		ToggleSetting{
+			id:       "fuzzy_auto_link",
+			label:    "Find issue key anywhere in project name",
+			category: "Projects",
+			getter: func() (bool, error) {
+				settings, err := api.GetUserSettings()
+				if err != nil {
+					return false, err
+				}
+				return settings.FuzzyAutoLink, nil
+			},
+			setter: func(enabled bool) error {
+				_, err := api.UpdateUserSettings(&dtos.UpdateUserSettingsInput{FuzzyAutoLink: &enabled})
+				return err
+			},
+		},
 		StaticSetting{
 			id:       "atlassian_integration_status",
 			label:    "Atlassian Status",
@@ -111,6 +189,9 @@ func GetAllSettings(db *database.DBWrapper) []Setting {
 				if err != nil {
 					return "Disabled"
 				}
+				if user.Integration.Atlassian.ReauthRequired {
+					return "Re-authentication required"
+				}
 				if user.Integration.Atlassian.Enabled {
 					return "Enabled"
 				}
@@ -22,7 +22,10 @@ func DashboardScreen(nav *ui.Navigator, ctx *app.AppContext) tview.Primitive {
 	_, _ = fmt.Fprintf(statusBox, "Server: %s\n", colorStatus(getServerStatus(ctx)))
 	_, _ = fmt.Fprintf(statusBox, "Server URL: %s\n", colorStatus(getServerURL(ctx)))
 	_, _ = fmt.Fprintf(statusBox, "User: %s\n", colorStatus(getUserStatus(ctx)))
+	_, _ = fmt.Fprintf(statusBox, "Workspace: %s\n", colorStatus(getWorkspaceStatus(ctx)))
+	_, _ = fmt.Fprintf(statusBox, "Timezone: %s\n", colorStatus(getTimezoneStatus(ctx)))
 	_, _ = fmt.Fprintf(statusBox, "Atlassian Integration: %s\n", colorStatus(getAtlassianStatus(ctx)))
+	_, _ = fmt.Fprintf(statusBox, "GitLab Integration: %s\n", colorStatus(getGitLabStatus(ctx)))
 
 	actions := tview.NewTextView().
 		SetDynamicColors(true).
@@ -71,17 +74,43 @@ func getUserStatus(ctx *app.AppContext) string {
 	return fmt.Sprintf("Logged in as %s", user.Email)
 }
 
+func getTimezoneStatus(ctx *app.AppContext) string {
+	user, err := ctx.API.GetCurrentUser()
+	if err != nil || user.Timezone == "" {
+		return "Not set - using local time"
+	}
+	return user.Timezone
+}
+
+func getWorkspaceStatus(ctx *app.AppContext) string {
+	if name := ctx.DB.Get(database.WorkspaceNameKey); name != "" {
+		return name
+	}
+	return "Personal"
+}
+
 func getAtlassianStatus(ctx *app.AppContext) string {
 	user, err := ctx.API.GetCurrentUser()
 	if err != nil {
 		return "Disabled"
 	}
+	if user.Integration.Atlassian.ReauthRequired {
+		return "Re-authentication required"
+	}
 	if user.Integration.Atlassian.Enabled {
 		return "Enabled"
 	}
 	return "Disabled"
 }
 
+func getGitLabStatus(ctx *app.AppContext) string {
+	user, err := ctx.API.GetCurrentUser()
+	if err != nil || !user.Integration.GitLab.Enabled {
+		return "Disabled"
+	}
+	return "Enabled - " + user.Integration.GitLab.BaseURL
+}
+
 func getServerURL(ctx *app.AppContext) string {
 	return ctx.DB.Get(database.ServerURLKey)
 }
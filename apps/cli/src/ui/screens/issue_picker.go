@@ -0,0 +1,155 @@
+package screens
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/ui"
+	"TimeTrack-shared/models"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// issueSearchDelay is how long the picker waits after the last key press
+// before searching, so typing does not send a request per character.
+const issueSearchDelay = 300 * time.Millisecond
+
+// IssuePickerScreen lets the user search Jira issues by text and pick one.
+// onSelect is called with the chosen issue, onCancel when the user leaves
+// without choosing.
+func IssuePickerScreen(nav *ui.Navigator, ctx *app.AppContext, query string, onSelect func(models.Issue), onCancel func()) tview.Primitive {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+
+	input := tview.NewInputField().
+		SetLabel("Search: ").
+		SetText(query).
+		SetFieldWidth(0)
+	input.SetBorder(true).SetTitle(" Jira Issues ")
+
+	list := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+	list.SetBorder(true).SetTitle(" Results ")
+
+	actionBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	_, _ = fmt.Fprintf(actionBar, "[yellow](Enter)[-] Search / Select  |  [yellow](Tab)[-] Switch  |  [yellow](Esc)[-] Cancel")
+
+	var (
+		mu     sync.Mutex
+		seq    int
+		timer  *time.Timer
+		issues []models.Issue
+	)
+
+	showIssues := func(found []models.Issue, err error) {
+		list.Clear()
+		issues = found
+		switch {
+		case err != nil:
+			list.SetTitle(" Results ")
+			list.AddItem("[red]"+tview.Escape(err.Error())+"[-]", "", 0, nil)
+		case len(found) == 0:
+			list.SetTitle(" Results ")
+			list.AddItem("No matching issues", "", 0, nil)
+		default:
+			list.SetTitle(fmt.Sprintf(" Results (%d) ", len(found)))
+			for _, issue := range found {
+				text := fmt.Sprintf("[yellow]%s[-] %s [gray][%s][-]", issue.Key, tview.Escape(issue.Summary), tview.Escape(issue.Status))
+				list.AddItem(text, "", 0, nil)
+			}
+		}
+	}
+
+	// search runs in the background. Results of a search that was superseded
+	// by a newer one are dropped.
+	search := func(text string) {
+		mu.Lock()
+		seq++
+		current := seq
+		mu.Unlock()
+
+		text = strings.TrimSpace(text)
+		if text == "" {
+			showIssues(nil, nil)
+			return
+		}
+		list.SetTitle(" Searching... ")
+
+		go func() {
+			found, err := ctx.API.SearchJiraIssues(text)
+			nav.App.QueueUpdateDraw(func() {
+				mu.Lock()
+				stale := current != seq
+				mu.Unlock()
+				if !stale {
+					showIssues(found, err)
+				}
+			})
+		}()
+	}
+
+	input.SetChangedFunc(func(text string) {
+		mu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(issueSearchDelay, func() {
+			nav.App.QueueUpdateDraw(func() { search(text) })
+		})
+		mu.Unlock()
+	})
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			mu.Lock()
+			if timer != nil {
+				timer.Stop()
+			}
+			mu.Unlock()
+			search(input.GetText())
+			nav.App.SetFocus(list)
+		case tcell.KeyTab:
+			nav.App.SetFocus(list)
+		case tcell.KeyEscape:
+			onCancel()
+		}
+	})
+
+	list.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		if index < len(issues) {
+			onSelect(issues[index])
+		}
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab:
+			nav.App.SetFocus(input)
+			return nil
+		case tcell.KeyEscape:
+			onCancel()
+			return nil
+		}
+		if event.Rune() != 0 {
+			// Typing in the list continues the search text.
+			nav.App.SetFocus(input)
+			input.SetText(input.GetText() + string(event.Rune()))
+			return nil
+		}
+		return event
+	})
+
+	flex.AddItem(input, 3, 0, true)
+	flex.AddItem(list, 0, 1, false)
+	flex.AddItem(actionBar, 1, 0, false)
+
+	search(query)
+
+	return flex
+}
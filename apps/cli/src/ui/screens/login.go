@@ -26,6 +26,7 @@ func LoginModal(nav *ui.Navigator, ctx *app.AppContext, exitOnCancel bool) tview
 			nav.Show(components.StyledModal("Login failed: "+err.Error(), func() { nav.Show(LoginModal(nav, ctx, exitOnCancel)) }))
 			return
 		}
+		ctx.DetectTimezone()
 		nav.Show(components.StyledModal("Login successful!", func() { nav.Show(DashboardScreen(nav, ctx)) }))
 	})
 	form.AddButton("Cancel", func() {
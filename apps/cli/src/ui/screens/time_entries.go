@@ -2,8 +2,10 @@ package screens
 
 import (
 	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/services"
 	"TimeTrack-cli/src/ui"
 	"TimeTrack-shared/models"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -13,7 +15,10 @@ import (
 	"github.com/rivo/tview"
 )
 
+const timeEntriesPageSize = 25
+
 func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDate time.Time) tview.Primitive {
+	loc := ctx.Location()
 	flex := tview.NewFlex().SetDirection(tview.FlexRow)
 
 	statsView := tview.NewTextView().
@@ -31,13 +36,30 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 
+	searchInput := tview.NewInputField().
+		SetLabel("/").
+		SetPlaceholder("words of the note, " + timeEntrySearchHelp).
+		SetFieldWidth(0)
+
+	var (
+		filter      services.TimeEntryFilter
+		filterErr   error
+		searchTimer *time.Timer
+	)
+
 	currentPage := 1
 	totalPages := 1
+	// totalEntries counts the entries on the server and those created offline.
+	totalEntries := int64(0)
+	// pageCursors holds the cursor of each page that was reached so far, so
+	// that the previous pages can be loaded again.
+	pageCursors := []string{""}
 
 	selectionMode := false
 	selectedRows := make(map[int]bool)
 	entriesCache := []*models.TimeEntry{}
 	projectMap := make(map[string]string)
+	pendingStatus := make(map[string]string)
 
 	prettyDuration := func(seconds float64) string {
 		d := time.Duration(seconds) * time.Second
@@ -68,17 +90,20 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 	}
 
 	updateTableTitle := func() {
+		counts := fmt.Sprintf("%d, page %d of %d", totalEntries, currentPage, totalPages)
 		if selectionMode {
-			table.SetTitle("[red] Time Entries (Selection Mode ON) ").SetBorderColor(tcell.ColorRed)
+			table.SetTitle("[red] Time Entries (Selection Mode ON) - " + counts + " ").SetBorderColor(tcell.ColorRed)
+		} else if !filter.IsZero() {
+			table.SetTitle(" Time Entries (Filtered) - " + counts + " ").SetBorderColor(tcell.ColorWhite)
 		} else {
-			table.SetTitle(" Time Entries ").SetBorderColor(tcell.ColorWhite)
+			table.SetTitle(" Time Entries - " + counts + " ").SetBorderColor(tcell.ColorWhite)
 		}
 	}
 
 	updateActionBar := func() {
-		actionBar.SetText("[yellow](D)[white] Delete   [yellow](R)[white] Report   [yellow](A)[white] Amend   " +
+		actionBar.SetText("[yellow](D)[white] Delete   [yellow](R)[white] Report   [yellow](A)[white] Amend   [yellow](X)[white] Discard Local Change   " +
 			"[yellow](S)[white] Toggle Selection Mode   [yellow](Space)[white] Select Row   " +
-			"[yellow](N)[white] Next Page   [yellow](P)[white] Prev Page   [yellow](Q)[white] Quit")
+			"[yellow](/)[white] Search   [yellow](N)[white] Next Page   [yellow](P)[white] Prev Page   [yellow](Q)[white] Quit")
 	}
 
 	showEntryListConfirm := func(title, action string, rows map[int]bool, singleRow int, onConfirm func()) {
@@ -93,8 +118,8 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 					}
 					listText += fmt.Sprintf("• %s (%s - %s)\n",
 						project,
-						entry.Period.Started.Format("2006-01-02 15:04"),
-						entry.Period.Ended.Format("15:04"))
+						entry.Period.Started.In(loc).Format("2006-01-02 15:04"),
+						entry.Period.Ended.In(loc).Format("15:04"))
 				}
 			}
 		} else if singleRow > 0 && singleRow-1 < len(entriesCache) {
@@ -105,8 +130,8 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 			}
 			listText += fmt.Sprintf("• %s (%s - %s)\n",
 				project,
-				entry.Period.Started.Format("2006-01-02 15:04"),
-				entry.Period.Ended.Format("15:04"))
+				entry.Period.Started.In(loc).Format("2006-01-02 15:04"),
+				entry.Period.Ended.In(loc).Format("15:04"))
 		}
 
 		modal := tview.NewModal().
@@ -115,6 +140,7 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 				if buttonLabel == "Confirm" {
 					onConfirm()
+					return
 				}
 				nav.Show(TimeEntriesScreen(nav, ctx, startDate, endDate))
 			})
@@ -127,7 +153,7 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 	deleteEntries := func(ids []string) {
 		var errs []string
 		for _, id := range ids {
-			if err := ctx.API.DeleteTimeEntry(id); err != nil {
+			if err := ctx.API.DeleteTimeEntry(id); err != nil && !errors.Is(err, services.ErrQueuedOffline) {
 				errs = append(errs, fmt.Sprintf("Failed to delete %s: %v", id, err))
 			}
 		}
@@ -143,22 +169,88 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 			nav.Show(errorModal)
 			return
 		}
-		loadData(currentPage)
+		nav.Show(TimeEntriesScreen(nav, ctx, startDate, endDate))
 	}
 
-	loadData = func(page int) {
-		statsView.Clear()
-		table.Clear()
-		selectedRows = map[int]bool{}
-		updateActionBar()
-		updateTableTitle()
+	discardPending := func(entryID string) {
+		ops, err := ctx.API.GetPendingOperations()
+		if err == nil {
+			for _, op := range ops {
+				if op.EntryRef() == entryID {
+					err = ctx.API.DiscardPendingOperation(op.ID)
+					if err != nil {
+						break
+					}
+				}
+			}
+		}
+		if err != nil {
+			errorModal := tview.NewModal().
+				SetText(fmt.Sprintf("[red]Discarding failed:\n\n%v", err)).
+				AddButtons([]string{"OK"}).
+				SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+					nav.Show(TimeEntriesScreen(nav, ctx, startDate, endDate))
+				})
+			errorModal.SetTitle("Error").SetBorder(true)
+			nav.Show(errorModal)
+			return
+		}
+		nav.Show(TimeEntriesScreen(nav, ctx, startDate, endDate))
+	}
 
-		stats, err := ctx.API.GetTimeEntryStatistics(startDate.Format(time.RFC3339), endDate.Format(time.RFC3339))
+	reportEntries := func(ids []string) {
+		results, err := ctx.API.ReportTimeEntries(ids)
 		if err != nil {
-			_, _ = fmt.Fprintf(statsView, "[red]Error loading stats: %v", err)
+			errorModal := tview.NewModal().
+				SetText(fmt.Sprintf("[red]Reporting failed:\n\n%v", err)).
+				AddButtons([]string{"OK"}).
+				SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+					nav.Show(TimeEntriesScreen(nav, ctx, startDate, endDate))
+				})
+			errorModal.SetTitle("Error").SetBorder(true)
+			nav.Show(errorModal)
 			return
 		}
 
+		entryByID := make(map[string]*models.TimeEntry)
+		for _, e := range entriesCache {
+			entryByID[e.ID] = e
+		}
+
+		resultText := ""
+		failed := 0
+		for _, r := range results {
+			label := r.ID
+			if e, ok := entryByID[r.ID]; ok {
+				project := projectMap[e.ProjectID]
+				if project == "" {
+					project = e.ProjectID
+				}
+				label = fmt.Sprintf("%s (%s)", project, e.Period.Started.In(loc).Format("2006-01-02 15:04"))
+			}
+			if r.Error != "" {
+				failed++
+				resultText += fmt.Sprintf("[red]✗ %s: %s\n", label, r.Error)
+			} else {
+				resultText += fmt.Sprintf("[green]✓ %s\n", label)
+			}
+		}
+
+		title := "Report Complete"
+		if failed > 0 {
+			title = fmt.Sprintf("Report Finished (%d failed)", failed)
+		}
+		resultModal := tview.NewModal().
+			SetText(resultText).
+			AddButtons([]string{"OK"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				nav.Show(TimeEntriesScreen(nav, ctx, startDate, endDate))
+			})
+		resultModal.SetTitle(title).SetBorder(true)
+		nav.Show(resultModal)
+	}
+
+	renderStats := func(stats *models.TimeEntryStatistics) {
 		projectIDs := []string{}
 		for _, p := range stats.EntriesPerProject {
 			projectIDs = append(projectIDs, p.ProjectID)
@@ -197,22 +289,113 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 			_, _ = fmt.Fprintf(statsView, "  %s: %s\n", name, prettyDuration(p.TotalTime))
 		}
 
-		entries, err := ctx.API.GetTimeEntries(startDate.Format(time.RFC3339), endDate.Format(time.RFC3339), page)
+		if len(stats.EntriesPerTag) > 0 {
+			_, _ = fmt.Fprintf(statsView, "\n[green]Per Tag:[white]\n")
+			for _, t := range stats.EntriesPerTag {
+				_, _ = fmt.Fprintf(statsView, "  %s: %s\n", t.Tag, prettyDuration(t.TotalTime))
+			}
+		}
+
+		if stats.BillableTime > 0 {
+			_, _ = fmt.Fprintf(statsView, "\n[green]Billable:[white] %s\n", prettyDuration(float64(stats.BillableTime)))
+			// Amounts are in cents, which fits most currencies.
+			for _, total := range stats.BillableTotals {
+				_, _ = fmt.Fprintf(statsView, "  %.2f %s for %s\n", float64(total.Amount)/100, total.Currency,
+					prettyDuration(float64(total.BillableTime)))
+			}
+		}
+	}
+
+	// mergePending records the sync state of entries with queued changes and
+	// counts the entries created offline. They are added to the list on the
+	// first page only, so that paging does not repeat them.
+	mergePending := func(entries []*models.TimeEntry, page int) []*models.TimeEntry {
+		pendingStatus = make(map[string]string)
+		ops, err := ctx.API.GetPendingOperations()
 		if err != nil {
-			table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf("[red]Error: %v", err)))
-			return
+			return entries
+		}
+		for _, op := range ops {
+			status := "[yellow]Pending " + op.Type
+			if op.Conflict != "" {
+				status = "[red]Conflict: " + op.Conflict
+			}
+			pendingStatus[op.EntryRef()] = status
+
+			// The server did not filter entries created offline, so they are
+			// only listed without a filter.
+			if op.Type != services.PendingCreate || op.Create == nil || !filter.IsZero() {
+				continue
+			}
+			if op.Create.Period.Start.Before(startDate) || op.Create.Period.Start.After(endDate) {
+				continue
+			}
+			totalEntries++
+			if page != 1 {
+				continue
+			}
+			entries = append(entries, &models.TimeEntry{
+				ID:        op.EntryRef(),
+				ProjectID: op.Create.ProjectID,
+				Period: models.TimePeriod{
+					Started: op.Create.Period.Start,
+					Ended:   op.Create.Period.End,
+				},
+				Note: op.Create.Note,
+			})
 		}
+		return entries
+	}
 
-		entriesCache = entries
+	loadData = func(page int) {
+		statsView.Clear()
+		table.Clear()
+		selectedRows = map[int]bool{}
+		updateActionBar()
+		updateTableTitle()
+
+		if ctx.Offline {
+			_, _ = fmt.Fprintf(statsView, "[red]Offline:[white] showing changes saved locally only\n")
+		}
 
-		if stats.TotalEntries > 0 {
-			totalPages = int((stats.TotalEntries + 24) / 25)
+		stats, err := ctx.API.GetTimeEntryStatistics(startDate.Format(time.RFC3339), endDate.Format(time.RFC3339))
+		if err != nil {
+			_, _ = fmt.Fprintf(statsView, "[red]Error loading stats: %v\n", err)
 		} else {
-			totalPages = 1
+			renderStats(stats)
+		}
+
+		if filterErr != nil {
+			table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf("[red]Search: %v", filterErr)))
+			return
+		}
+
+		result, err := ctx.API.GetTimeEntries(startDate.Format(time.RFC3339), endDate.Format(time.RFC3339), pageCursors[page-1], timeEntriesPageSize, filter)
+		if err != nil && !services.IsUnreachable(err) {
+			table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf("[red]Error: %v", err)))
+			return
 		}
 
+		entries := []*models.TimeEntry{}
+		totalPages = 1
+		totalEntries = 0
+		if result != nil {
+			totalEntries = result.Total
+			for i := range result.Entries {
+				entries = append(entries, &result.Entries[i])
+			}
+			if result.Total > 0 {
+				totalPages = int((result.Total + timeEntriesPageSize - 1) / timeEntriesPageSize)
+			}
+			if result.NextCursor != "" {
+				pageCursors = append(pageCursors[:page], result.NextCursor)
+			}
+		}
+		entriesCache = mergePending(entries, page)
+		updateTableTitle()
+
 		entryIDs := []string{}
-		for _, e := range entries {
+		for _, e := range entriesCache {
 			entryIDs = append(entryIDs, e.ProjectID)
 		}
 		if len(entryIDs) > 0 {
@@ -227,17 +410,20 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 			table.SetCell(0, col, tview.NewTableCell(fmt.Sprintf("[yellow]%s", h)).SetSelectable(false))
 		}
 
-		for row, e := range entries {
+		for row, e := range entriesCache {
 			project := projectMap[e.ProjectID]
 			if project == "" {
 				project = e.ProjectID
 			}
-			start := e.Period.Started.Format("2006-01-02 15:04")
-			end := e.Period.Ended.Format("2006-01-02 15:04")
+			start := e.Period.Started.In(loc).Format("2006-01-02 15:04")
+			end := e.Period.Ended.In(loc).Format("2006-01-02 15:04")
 			duration := prettyDuration(e.Period.Ended.Sub(e.Period.Started).Seconds())
 			reported := "[red]No"
 			if e.Reported != nil && e.Reported.ReportedAt != nil {
-				reported = fmt.Sprintf("[green]Yes (%s)", e.Reported.ReportedAt.Format("2006-01-02"))
+				reported = fmt.Sprintf("[green]Yes (%s)", e.Reported.ReportedAt.In(loc).Format("2006-01-02"))
+			}
+			if status, ok := pendingStatus[e.ID]; ok {
+				reported = status
 			}
 
 			values := []string{project, start, end, duration, e.Note, reported}
@@ -254,11 +440,69 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 
 	updateActionBar()
 
+	// applySearch filters the entries by the text of the search prompt and
+	// shows the first page of the result.
+	applySearch := func(text string) {
+		filter, filterErr = parseTimeEntrySearch(ctx, text)
+		currentPage = 1
+		pageCursors = []string{""}
+		loadData(currentPage)
+	}
+
+	showSearch := func(show bool) {
+		if show {
+			flex.ResizeItem(searchInput, 1, 0)
+			nav.App.SetFocus(searchInput)
+			return
+		}
+		if searchInput.GetText() == "" {
+			flex.ResizeItem(searchInput, 0, 0)
+		}
+		nav.App.SetFocus(table)
+	}
+
+	// The filters apply while typing, once no key was pressed for a moment.
+	searchInput.SetChangedFunc(func(text string) {
+		if searchTimer != nil {
+			searchTimer.Stop()
+		}
+		searchTimer = time.AfterFunc(issueSearchDelay, func() {
+			nav.App.QueueUpdateDraw(func() {
+				if searchInput.GetText() == text {
+					applySearch(text)
+				}
+			})
+		})
+	})
+
+	searchInput.SetDoneFunc(func(key tcell.Key) {
+		if searchTimer != nil {
+			searchTimer.Stop()
+		}
+		switch key {
+		case tcell.KeyEnter:
+			applySearch(searchInput.GetText())
+		case tcell.KeyEscape:
+			searchInput.SetText("")
+			if searchTimer != nil {
+				searchTimer.Stop()
+			}
+			applySearch("")
+		}
+		showSearch(false)
+	})
+
 	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if searchInput.HasFocus() {
+			return event
+		}
 		row, _ := table.GetSelection()
 		switch strings.ToLower(string(event.Rune())) {
+		case "/":
+			showSearch(true)
+			return nil
 		case "n":
-			if currentPage < totalPages {
+			if currentPage < totalPages && currentPage < len(pageCursors) {
 				currentPage++
 				loadData(currentPage)
 			}
@@ -297,9 +541,21 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 			}
 		case "r":
 			if selectionMode && len(selectedRows) > 0 {
-				showEntryListConfirm("Bulk Report", "Report", selectedRows, -1, func() { /* TODO: bulk report */ })
+				showEntryListConfirm("Bulk Report", "Report", selectedRows, -1, func() {
+					ids := []string{}
+					for r := range selectedRows {
+						if r-1 >= 0 && r-1 < len(entriesCache) {
+							ids = append(ids, entriesCache[r-1].ID)
+						}
+					}
+					reportEntries(ids)
+				})
 			} else if !selectionMode && row > 0 {
-				showEntryListConfirm("Report Entry", "Report", nil, row, func() { /* TODO: report */ })
+				showEntryListConfirm("Report Entry", "Report", nil, row, func() {
+					if row-1 >= 0 && row-1 < len(entriesCache) {
+						reportEntries([]string{entriesCache[row-1].ID})
+					}
+				})
 			}
 		case "a":
 			if selectionMode && len(selectedRows) > 0 {
@@ -312,6 +568,14 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 				warningModal.SetTitle("Bulk Amend Blocked").SetBorder(true)
 				nav.Show(warningModal)
 			}
+		case "x":
+			if !selectionMode && row > 0 && row-1 < len(entriesCache) {
+				if _, ok := pendingStatus[entriesCache[row-1].ID]; ok {
+					showEntryListConfirm("Discard Local Change", "Discard the local changes of", nil, row, func() {
+						discardPending(entriesCache[row-1].ID)
+					})
+				}
+			}
 		case "q":
 			nav.Stop()
 		}
@@ -320,6 +584,7 @@ func TimeEntriesScreen(nav *ui.Navigator, ctx *app.AppContext, startDate, endDat
 
 	flex.AddItem(statsView, 10, 0, false)
 	flex.AddItem(table, 0, 1, true)
+	flex.AddItem(searchInput, 0, 0, false)
 	flex.AddItem(actionBar, 1, 0, false)
 
 	loadData(currentPage)
@@ -0,0 +1,66 @@
+package screens
+
+import (
+	"TimeTrack-cli/src/app"
+	"TimeTrack-cli/src/services"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeEntrySearchHelp lists the terms the search prompt of the time entries
+// understands besides words of the note.
+const timeEntrySearchHelp = "project:<name> tag:<tag> reported:yes|no integration:<type> min:<30m> max:<2h>"
+
+// parseTimeEntrySearch turns the text of the search prompt into a filter.
+// Words are searched in the notes of the entries, and key:value terms such as
+// "tag:meeting" or "min:1h" narrow the list further.
+func parseTimeEntrySearch(ctx *app.AppContext, text string) (services.TimeEntryFilter, error) {
+	var filter services.TimeEntryFilter
+	var words []string
+	for _, term := range strings.Fields(text) {
+		key, value, found := strings.Cut(term, ":")
+		if !found || value == "" {
+			words = append(words, term)
+			continue
+		}
+		switch key = strings.ToLower(key); key {
+		case "project":
+			project, err := ctx.API.GetProjectByName(value)
+			if err != nil || project == nil {
+				return filter, fmt.Errorf("unknown project %q", value)
+			}
+			filter.ProjectIDs = append(filter.ProjectIDs, project.ID)
+		case "tag":
+			filter.Tags = append(filter.Tags, strings.ToLower(value))
+		case "reported":
+			switch strings.ToLower(value) {
+			case "yes", "true":
+				reported := true
+				filter.Reported = &reported
+			case "no", "false":
+				reported := false
+				filter.Reported = &reported
+			default:
+				return filter, fmt.Errorf("reported must be yes or no, not %q", value)
+			}
+		case "integration":
+			filter.Integration = strings.ToLower(value)
+		case "min", "max":
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return filter, fmt.Errorf("invalid duration %q, use e.g. 30m or 1h30m", value)
+			}
+			if key == "min" {
+				filter.MinDuration = d
+			} else {
+				filter.MaxDuration = d
+			}
+		default:
+			// Not a filter, e.g. a URL in the note.
+			words = append(words, term)
+		}
+	}
+	filter.Text = strings.Join(words, " ")
+	return filter, nil
+}
@@ -19,3 +19,28 @@ func Confirm(message string) bool {
 
 	return slices.Contains(allowedConfirmations, strings.ToLower(confirmation))
 }
+
+// Choose asks the user to pick one of the options by typing its key and
+// returns the key, or "" when the input matches none of them.
+func Choose(message string, options map[string]string) string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	fmt.Println(message)
+	for _, key := range keys {
+		fmt.Printf("  (%s) %s\n", key, options[key])
+	}
+	var choice string
+	_, err := fmt.Scanln(&choice)
+	if err != nil {
+		return ""
+	}
+	choice = strings.ToLower(strings.TrimSpace(choice))
+	if _, ok := options[choice]; !ok {
+		return ""
+	}
+	return choice
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// DetectTimezone returns the IANA name of the local timezone, or an empty
+// string when it cannot be determined.
+func DetectTimezone() string {
+	if tz := os.Getenv("TZ"); tz != "" && tz != "Local" {
+		if _, err := time.LoadLocation(tz); err == nil {
+			return tz
+		}
+	}
+	if name := time.Local.String(); name != "Local" && name != "" {
+		return name
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(target, "zoneinfo/"); ok {
+			return name
+		}
+	}
+	if content, err := os.ReadFile("/etc/timezone"); err == nil {
+		return strings.TrimSpace(string(content))
+	}
+	return ""
+}
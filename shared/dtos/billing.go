@@ -0,0 +1,22 @@
+package dtos
+
+import "time"
+
+type CreateClientInput struct {
+	Name string `json:"name" binding:"required,min=1"`
+}
+
+type UpdateClientInput struct {
+	Name *string `json:"name" binding:"omitempty,min=1"`
+}
+
+// CreateRateInput sets the hourly rate of a client, a project or a user, or of
+// a user on a client or project.
+type CreateRateInput struct {
+	ClientID      string     `json:"client_id" binding:"omitempty,uuid"`
+	ProjectID     string     `json:"project_id" binding:"omitempty,uuid"`
+	UserID        string     `json:"user_id" binding:"omitempty,uuid"`
+	HourlyRate    int64      `json:"hourly_rate" binding:"min=0"`             // in the minor unit of the currency, e.g. cents
+	Currency      string     `json:"currency" binding:"required,len=3,alpha"` // ISO 4217 code, e.g. "EUR"
+	EffectiveFrom *time.Time `json:"effective_from"`                          // defaults to now
+}
@@ -1,7 +1,7 @@
 package dtos
 
 type IntegrationInfo struct {
-	Type       string `bson:"type" json:"type" binding:"required,oneof=jira"`
+	Type       string `bson:"type" json:"type" binding:"required"`
 	Key        string `bson:"key" json:"key"`
 	ExternalID string `bson:"external_id" json:"external_id"`
 }
@@ -9,9 +9,13 @@ type IntegrationInfo struct {
 type CreateProjectInput struct {
 	Name        string          `json:"name" binding:"required,min=1"`
 	Integration IntegrationInfo `bson:"integration" json:"integration" binding:"omitempty"`
+	ClientID    string          `json:"client_id" binding:"omitempty,uuid"`
+	Billable    bool            `json:"billable"` // default for new time entries
 }
 
 type UpdateProjectInput struct {
 	Name        *string          `json:"name" binding:"omitempty,min=1"`
 	Integration *IntegrationInfo `bson:"integration" json:"integration" binding:"omitempty"`
+	ClientID    *string          `json:"client_id"` // empty to remove the client
+	Billable    *bool            `json:"billable"`
 }
@@ -0,0 +1,6 @@
+package dtos
+
+type UpdateUserSettingsInput struct {
+	AutoLinkJira  *bool `json:"auto_link_jira"`
+	FuzzyAutoLink *bool `json:"fuzzy_auto_link"`
+}
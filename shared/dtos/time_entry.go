@@ -11,10 +11,42 @@ type CreateTimeEntryInput struct {
 	ProjectID string     `json:"project_id" binding:"required,uuid"`
 	Period    TimePeriod `json:"period" binding:"required"`
 	Note      string     `json:"note" binding:"omitempty,max=1024"`
+	Billable  *bool      `json:"billable"` // defaults to the setting of the project
+	Tags      []string   `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
 }
 
 type UpdateTimeEntryInput struct {
 	ProjectID *string     `json:"project_id" binding:"omitempty,uuid"`
 	Period    *TimePeriod `json:"period" binding:"omitempty"`
 	Note      *string     `json:"note" binding:"omitempty,max=1024"`
+	Billable  *bool       `json:"billable"`
+	Tags      *[]string   `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"` // replaces the tags, an empty list removes them
+}
+
+type StartTimerInput struct {
+	ProjectID string   `json:"project_id" binding:"required,uuid"`
+	Note      string   `json:"note" binding:"omitempty,max=1024"`
+	Billable  *bool    `json:"billable"` // defaults to the setting of the project
+	Tags      []string `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+type StopTimerInput struct {
+	Note *string `json:"note" binding:"omitempty,max=1024"`
+}
+
+type ReportTimeEntriesInput struct {
+	IDs []string `json:"ids" binding:"required,min=1,dive,uuid"`
+}
+
+type BulkTimeEntryRow struct {
+	ProjectName string     `json:"project_name"`
+	Period      TimePeriod `json:"period"`
+	Note        string     `json:"note"`
+}
+
+type BulkCreateTimeEntriesInput struct {
+	Entries        []BulkTimeEntryRow `json:"entries" binding:"required,min=1,max=5000"`
+	DryRun         bool               `json:"dry_run"`         // validate only, insert nothing
+	CreateProjects bool               `json:"create_projects"` // create missing projects instead of rejecting the row
+	Report         bool               `json:"report"`          // report imported entries to linked integrations
 }
@@ -0,0 +1,9 @@
+package dtos
+
+type SubmitTimesheetInput struct {
+	Week string `json:"week" binding:"required"` // ISO week, e.g. "2026-W38"
+}
+
+type ReviewTimesheetInput struct {
+	Comment string `json:"comment" binding:"max=1024"`
+}
@@ -0,0 +1,11 @@
+package dtos
+
+type UpdateUserInput struct {
+	OverlapPolicy *string `json:"overlap_policy" binding:"omitempty,oneof=reject warn allow"`
+	Timezone      *string `json:"timezone" binding:"omitempty,timezone"`
+}
+
+type ConnectGitLabInput struct {
+	BaseURL     string `json:"base_url" binding:"omitempty,url"`
+	AccessToken string `json:"access_token" binding:"required"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+import "time"
+
+// DriftResolution resolves one difference found by reconciliation. "pull"
+// makes the local entry match the integration, "push" makes the integration
+// match the local entry.
+type DriftResolution struct {
+	Kind        string `json:"kind" binding:"required,oneof=missing changed orphaned"`
+	Action      string `json:"action" binding:"required,oneof=pull push"`
+	TimeEntryID string `json:"time_entry_id"` // required for missing and changed
+	ProjectID   string `json:"project_id"`    // required for orphaned
+	WorklogID   string `json:"worklog_id"`    // required for orphaned
+}
+
+type ResolveDriftInput struct {
+	Items []DriftResolution `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+type ImportWorklogsInput struct {
+	From   time.Time `json:"from" binding:"required"`
+	To     time.Time `json:"to" binding:"required"`
+	DryRun bool      `json:"dry_run"`
+}
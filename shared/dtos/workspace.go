@@ -0,0 +1,14 @@
+package dtos
+
+type CreateWorkspaceInput struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type AddWorkspaceMemberInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"omitempty,oneof=admin manager member"` // defaults to member
+}
+
+type UpdateWorkspaceMemberInput struct {
+	Role string `json:"role" binding:"required,oneof=admin manager member"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// Scopes of a personal access token, from least to most access.
+const (
+	TokenScopeRead  = "read"  // read-only access
+	TokenScopeWrite = "write" // read access and changes to time entries
+	TokenScopeAdmin = "admin" // same access as a login
+)
+
+// APIToken is a personal access token that scripts use instead of a login.
+type APIToken struct {
+	ID         string     `bson:"_id" json:"id"`
+	UserID     string     `bson:"user_id" json:"-"`
+	Name       string     `bson:"name" json:"name"`
+	Scope      string     `bson:"scope" json:"scope"`
+	Prefix     string     `bson:"prefix" json:"prefix"` // start of the token, to tell tokens apart
+	TokenHash  string     `bson:"token_hash" json:"-"`  // SHA-256 of the token
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `bson:"revoked_at,omitempty" json:"-"`
+}
+
+// CreatedAPIToken is returned once when a token is created. The token itself
+// cannot be read again.
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditEvent records a request that was denied by the permission checks.
+type AuditEvent struct {
+	ID          string    `bson:"_id" json:"id"`
+	UserID      string    `bson:"user_id" json:"user_id"`
+	WorkspaceID string    `bson:"workspace_id,omitempty" json:"workspace_id,omitempty"`
+	Role        string    `bson:"role,omitempty" json:"role,omitempty"` // empty when the user is not a member
+	Permission  string    `bson:"permission" json:"permission"`
+	Resource    string    `bson:"resource" json:"resource"` // method and route, e.g. "DELETE /api/v1/projects/:id"
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// Client is who the time on its projects is billed to.
+type Client struct {
+	ID          string     `bson:"_id" json:"id"`
+	Name        string     `bson:"name" json:"name"`
+	OwnerID     string     `bson:"owner_id" json:"owner_id"`
+	WorkspaceID string     `bson:"workspace_id,omitempty" json:"workspace_id,omitempty"` // empty for personal clients
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updated_at"`
+	DeletedAt   *time.Time `bson:"deleted_at,omitempty" json:"-"`
+}
+
+// Rate is an hourly rate for a client, a project or a user, or a user on a
+// client or project, from its effective date until a later rate for the same
+// target takes over. Rates are not edited, so a new rate only changes billing
+// from its effective date on.
+type Rate struct {
+	ID            string    `bson:"_id" json:"id"`
+	OwnerID       string    `bson:"owner_id" json:"owner_id"`                             // who set the rate
+	WorkspaceID   string    `bson:"workspace_id,omitempty" json:"workspace_id,omitempty"` // empty for personal rates
+	ClientID      string    `bson:"client_id,omitempty" json:"client_id,omitempty"`
+	ProjectID     string    `bson:"project_id,omitempty" json:"project_id,omitempty"`
+	UserID        string    `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	HourlyRate    int64     `bson:"hourly_rate" json:"hourly_rate"` // in the minor unit of the currency, e.g. cents
+	Currency      string    `bson:"currency" json:"currency"`       // ISO 4217 code, e.g. "EUR"
+	EffectiveFrom time.Time `bson:"effective_from" json:"effective_from"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// Issue is an issue in an integration, as returned by issue search.
+type Issue struct {
+	ID      string `json:"id"`      // ID in the integration system
+	Key     string `json:"key"`     // e.g. "MNT-123"
+	Summary string `json:"summary"` // title of the issue
+	Status  string `json:"status"`  // e.g. "In Progress"
+}
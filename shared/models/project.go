@@ -15,7 +15,17 @@ type Project struct {
 	Name        string          `bson:"name" json:"name"`
 	Integration IntegrationInfo `bson:"integration" json:"integration"`
 	OwnerID     string          `bson:"owner_id" json:"owner_id"`
+	WorkspaceID string          `bson:"workspace_id,omitempty" json:"workspace_id,omitempty"` // empty for personal projects
+	ClientID    string          `bson:"client_id,omitempty" json:"client_id,omitempty"`
+	Billable    bool            `bson:"billable" json:"billable"` // default for new time entries
 	CreatedAt   time.Time       `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time       `bson:"updated_at" json:"updated_at"`
 	DeletedAt   *time.Time      `bson:"deleted_at,omitempty" json:"-"`
 }
+
+// ProjectPage is a page of projects sorted by name.
+type ProjectPage struct {
+	Projects   []Project `json:"projects"`
+	Total      int64     `json:"total"`       // number of projects that match on all pages
+	NextCursor string    `json:"next_cursor"` // fetches the following page, empty on the last page
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// Session is a login of a user on a device. It holds the refresh token that
+// the device uses to get new access tokens.
+type Session struct {
+	ID         string     `bson:"_id" json:"id"`
+	UserID     string     `bson:"user_id" json:"-"`
+	Email      string     `bson:"email" json:"-"`
+	TokenHash  string     `bson:"token_hash" json:"-"` // SHA-256 of the refresh token
+	DeviceName string     `bson:"device_name" json:"device_name"`
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	LastUsedAt time.Time  `bson:"last_used_at" json:"last_used_at"`
+	ExpiresAt  time.Time  `bson:"expires_at" json:"expires_at"`
+	RevokedAt  *time.Time `bson:"revoked_at,omitempty" json:"-"`
+	Current    bool       `bson:"-" json:"current"` // the session the request was made with
+}
+
+type AuthTokens struct {
+	Token        string    `json:"token"`                   // access token for the Authorization header
+	ExpiresAt    time.Time `json:"expires_at"`              // expiry of the access token
+	RefreshToken string    `json:"refresh_token,omitempty"` // only returned on login
+	SessionID    string    `json:"session_id"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+type UserSettings struct {
+	OwnerID       string    `bson:"_id" json:"owner_id"`
+	AutoLinkJira  bool      `bson:"auto_link_jira" json:"auto_link_jira"`   // link new projects to the Jira issue named in the project name
+	FuzzyAutoLink bool      `bson:"fuzzy_auto_link" json:"fuzzy_auto_link"` // find the issue key anywhere in the name instead of requiring an exact match
+	UpdatedAt     time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// DefaultUserSettings returns the settings used until the user changes them.
+func DefaultUserSettings(ownerID string) *UserSettings {
+	return &UserSettings{
+		OwnerID:       ownerID,
+		AutoLinkJira:  true,
+		FuzzyAutoLink: false,
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+type SyncJob struct {
+	ID            string     `bson:"_id" json:"id"`
+	OwnerID       string     `bson:"owner_id" json:"owner_id"`
+	TimeEntryID   string     `bson:"time_entry_id" json:"time_entry_id"`
+	Integration   string     `bson:"integration" json:"integration"` // e.g. "jira"
+	IssueID       string     `bson:"issue_id" json:"issue_id"`       // e.g. "MNT-123"
+	Operation     string     `bson:"operation" json:"operation"`     // "create", "update" or "delete"
+	Status        string     `bson:"status" json:"status"`           // "pending", "failed" or "done"
+	Attempts      int        `bson:"attempts" json:"attempts"`
+	LastError     string     `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `bson:"next_attempt_at" json:"next_attempt_at"`
+	CompletedAt   *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `bson:"updated_at" json:"updated_at"`
+}
@@ -6,8 +6,8 @@ import (
 
 type TimePeriod struct {
 	Started  time.Time `bson:"started" json:"started"`
-	Ended    time.Time `bson:"ended" json:"ended"`
-	Duration int       `bson:"duration" json:"duration"` // duration in seconds
+	Ended    time.Time `bson:"ended,omitempty" json:"ended"` // zero while the timer is still running
+	Duration int       `bson:"duration" json:"duration"`     // duration in seconds
 }
 
 type ReportStatus struct {
@@ -16,6 +16,8 @@ type ReportStatus struct {
 	ExternalID  string     `bson:"external_id" json:"external_id"` // e.g. "12345" from Jira
 	ReportedAt  *time.Time `bson:"reported_at,omitempty" json:"reported_at,omitempty"`
 	UpdatedAt   *time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	CheckedAt   *time.Time `bson:"checked_at,omitempty" json:"checked_at,omitempty"` // last reconciliation with the integration
+	Drift       string     `bson:"drift,omitempty" json:"drift,omitempty"`           // DriftMissing or DriftChanged when the worklog no longer matches
 }
 
 type TimeEntry struct {
@@ -24,10 +26,47 @@ type TimeEntry struct {
 	OwnerID   string        `bson:"owner_id" json:"owner_id"`
 	Period    TimePeriod    `bson:"period" json:"period"`
 	Note      string        `bson:"note" json:"note"`
+	Billable  bool          `bson:"billable" json:"billable"`
+	Tags      []string      `bson:"tags,omitempty" json:"tags,omitempty"` // lower case, e.g. "meeting" or "code-review"
 	Reported  *ReportStatus `bson:"reported,omitempty" json:"reported,omitempty"`
 	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time     `bson:"updated_at" json:"updated_at"`
 	DeletedAt *time.Time    `bson:"deleted_at,omitempty" json:"-"`
+	Running   bool          `bson:"running,omitempty" json:"-"`   // set while the timer runs, a user can have one such entry
+	Conflicts []TimeEntry   `bson:"-" json:"conflicts,omitempty"` // overlapping entries, set when the overlap policy only warns
+}
+
+// TimeEntryPage is a page of time entries, newest first.
+type TimeEntryPage struct {
+	Entries    []TimeEntry `json:"entries"`
+	Total      int64       `json:"total"`       // number of entries that match on all pages
+	NextCursor string      `json:"next_cursor"` // fetches the following page, empty on the last page
+}
+
+type TimeEntryReportResult struct {
+	ID       string        `json:"id"`
+	Reported *ReportStatus `json:"reported,omitempty"`
+	Error    string        `json:"error,omitempty"` // set when the entry could not be reported
+}
+
+// BulkRowOverlapError is the error of a bulk row that overlaps other rows of
+// the same request, which are listed in Overlaps.
+const BulkRowOverlapError = "overlaps other rows of the import"
+
+type BulkTimeEntryRowResult struct {
+	Row            int        `json:"row"` // index of the row in the request
+	Entry          *TimeEntry `json:"entry,omitempty"`
+	ProjectCreated bool       `json:"project_created,omitempty"` // the project did not exist and is (or would be) created
+	Overlaps       []int      `json:"overlaps,omitempty"`        // indexes of the rows of the request that this row overlaps
+	Errors         []string   `json:"errors,omitempty"`
+}
+
+type BulkTimeEntriesResult struct {
+	DryRun   bool                     `json:"dry_run"`
+	Valid    int                      `json:"valid"`
+	Invalid  int                      `json:"invalid"`
+	Inserted int                      `json:"inserted"`
+	Rows     []BulkTimeEntryRowResult `json:"rows"`
 }
 
 type TimeEntryStatPerDate struct {
@@ -40,10 +79,35 @@ type TimeEntryPerProject struct {
 	TotalTime float64 `bson:"total_time" json:"total_time"` // total time in seconds
 }
 
+type TimeEntryPerTag struct {
+	Tag       string  `bson:"tag" json:"tag"`
+	TotalTime float64 `bson:"total_time" json:"total_time"` // total time in seconds
+}
+
+// TimeEntryBillable is the billable time on a project charged in a currency.
+type TimeEntryBillable struct {
+	ProjectID    string `json:"project_id"`
+	Currency     string `json:"currency"`      // empty for time no rate applies to
+	BillableTime int64  `json:"billable_time"` // total time in seconds
+	Amount       int64  `json:"amount"`        // in the minor unit of the currency, e.g. cents
+}
+
+// BillableTotal is the billable time and amount of all projects in a currency.
+type BillableTotal struct {
+	Currency     string `json:"currency"`
+	BillableTime int64  `json:"billable_time"` // total time in seconds
+	Amount       int64  `json:"amount"`        // in the minor unit of the currency, e.g. cents
+}
+
 type TimeEntryStatistics struct {
-	TotalEntries      int64                  `json:"total_entries"`       // total number of time entries used for statistics
-	TotalTime         int64                  `json:"total_time"`          // total time in seconds
-	Format            string                 `json:"format"`              // e.g. "d" for days, "w" for weeks, "m" for months
-	EntriesPerDate    []TimeEntryStatPerDate `json:"entries_per_date"`    // list of time entries per date in the specified format
-	EntriesPerProject []TimeEntryPerProject  `json:"entries_per_project"` // list of time entries per project
+	TotalEntries       int64                  `json:"total_entries"`        // total number of time entries used for statistics
+	TotalTime          int64                  `json:"total_time"`           // total time in seconds
+	Format             string                 `json:"format"`               // e.g. "d" for days, "w" for weeks, "m" for months
+	Timezone           string                 `json:"timezone"`             // IANA timezone the dates are grouped in
+	EntriesPerDate     []TimeEntryStatPerDate `json:"entries_per_date"`     // list of time entries per date in the specified format
+	EntriesPerProject  []TimeEntryPerProject  `json:"entries_per_project"`  // list of time entries per project
+	EntriesPerTag      []TimeEntryPerTag      `json:"entries_per_tag"`      // time per tag, an entry with several tags counts for each
+	BillableTime       int64                  `json:"billable_time"`        // time in seconds of the billable entries
+	BillablePerProject []TimeEntryBillable    `json:"billable_per_project"` // billable time and amount per project and currency
+	BillableTotals     []BillableTotal        `json:"billable_totals"`      // billable time and amount per currency
 }
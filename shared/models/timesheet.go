@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Statuses of a timesheet. Submitted and approved timesheets lock the time
+// entries of their week, a rejected timesheet can be fixed and submitted again.
+const (
+	TimesheetSubmitted = "submitted"
+	TimesheetApproved  = "approved"
+	TimesheetRejected  = "rejected"
+)
+
+// Timesheet is the time a user logged against the projects of a workspace in an
+// ISO week, submitted for approval by a manager of the workspace.
+type Timesheet struct {
+	ID           string      `bson:"_id" json:"id"`
+	OwnerID      string      `bson:"owner_id" json:"owner_id"`
+	WorkspaceID  string      `bson:"workspace_id" json:"workspace_id"`
+	Week         string      `bson:"week" json:"week"`   // ISO week, e.g. "2026-W38"
+	Start        time.Time   `bson:"start" json:"start"` // Monday 00:00 in the timezone of the owner
+	End          time.Time   `bson:"end" json:"end"`     // exclusive, the Monday after
+	Status       string      `bson:"status" json:"status"`
+	TotalTime    int64       `bson:"total_time" json:"total_time"` // seconds
+	TotalEntries int64       `bson:"total_entries" json:"total_entries"`
+	SubmittedAt  time.Time   `bson:"submitted_at" json:"submitted_at"`
+	ReviewedBy   string      `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time  `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	Comment      string      `bson:"comment,omitempty" json:"comment,omitempty"` // from the reviewer
+	Entries      []TimeEntry `bson:"-" json:"entries,omitempty"`                 // only set when a single timesheet is fetched
+	CreatedAt    time.Time   `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time   `bson:"updated_at" json:"updated_at"`
+}
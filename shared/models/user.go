@@ -4,21 +4,43 @@ import (
 	"time"
 )
 
+// Overlap policies decide what happens when a time entry covers time that is
+// already covered by another entry of the same user.
+const (
+	OverlapPolicyReject = "reject" // refuse the entry (default)
+	OverlapPolicyWarn   = "warn"   // save the entry and return the conflicts
+	OverlapPolicyAllow  = "allow"  // save the entry without checking
+)
+
 type User struct {
-	ID          string          `bson:"_id" json:"id"`
-	Email       string          `bson:"email" json:"email"`
-	Password    string          `bson:"password" json:"password,omitempty"`
-	DeletedAt   time.Time       `bson:"deleted_at,omitempty" json:"-"`
-	CreatedAt   time.Time       `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time       `bson:"updated_at" json:"updated_at"`
-	Integration UserIntegration `bson:"integration" json:"integration"`
+	ID            string          `bson:"_id" json:"id"`
+	Email         string          `bson:"email" json:"email"`
+	Password      string          `bson:"password" json:"password,omitempty"`
+	DeletedAt     time.Time       `bson:"deleted_at,omitempty" json:"-"`
+	CreatedAt     time.Time       `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time       `bson:"updated_at" json:"updated_at"`
+	Integration   UserIntegration `bson:"integration" json:"integration"`
+	OverlapPolicy string          `bson:"overlap_policy,omitempty" json:"overlap_policy,omitempty"` // empty means OverlapPolicyReject
+	Timezone      string          `bson:"timezone,omitempty" json:"timezone,omitempty"`             // IANA name, e.g. "Europe/Stockholm". Empty means UTC
 }
 
 type UserIntegration struct {
 	Atlassian AtlassianIntegration `bson:"atlassian" json:"atlassian"`
+	GitLab    GitLabIntegration    `bson:"gitlab" json:"gitlab"`
 }
 
 type AtlassianIntegration struct {
+	Enabled        bool      `bson:"enabled" json:"enabled"`
+	AccessToken    string    `bson:"access_token" json:"access_token"`
+	RefreshToken   string    `bson:"refresh_token" json:"refresh_token"`
+	ExpiresAt      time.Time `bson:"expires_at" json:"expires_at"`
+	ReauthRequired bool      `bson:"reauth_required" json:"reauth_required"` // set when the refresh token is rejected
+	CloudID        string    `bson:"cloud_id" json:"cloud_id"`               // Jira site of the user, cached on first use
+	AccountID      string    `bson:"account_id" json:"account_id"`           // Atlassian account of the user, cached with CloudID
+}
+
+type GitLabIntegration struct {
 	Enabled     bool   `bson:"enabled" json:"enabled"`
-	AccessToken string `bson:"access_token" json:"access_token"`
+	BaseURL     string `bson:"base_url" json:"base_url"`         // e.g. "https://gitlab.com"
+	AccessToken string `bson:"access_token" json:"access_token"` // personal access token with the api scope
 }
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	DriftMissing  = "missing"  // the entry is reported but its worklog no longer exists
+	DriftChanged  = "changed"  // the worklog duration differs from the entry
+	DriftOrphaned = "orphaned" // the worklog is not linked to any entry
+)
+
+// Worklog is time logged on an issue in an integration.
+type Worklog struct {
+	ID       string    `json:"id"`
+	IssueID  string    `json:"issue_id"`            // issue the worklog is logged on
+	IssueKey string    `json:"issue_key,omitempty"` // e.g. "MNT-123", set when importing
+	Started  time.Time `json:"started"`
+	Duration int       `json:"duration"` // duration in seconds
+	Comment  string    `json:"comment"`
+}
+
+// WorklogDrift is a difference between a local time entry and the worklogs in
+// the integration.
+type WorklogDrift struct {
+	Kind      string     `json:"kind"` // DriftMissing, DriftChanged or DriftOrphaned
+	ProjectID string     `json:"project_id"`
+	IssueID   string     `json:"issue_id"`
+	TimeEntry *TimeEntry `json:"time_entry,omitempty"` // not set for orphaned worklogs
+	Worklog   *Worklog   `json:"worklog,omitempty"`    // not set for missing worklogs
+}
+
+type ReconcileReport struct {
+	Integration string         `json:"integration"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	Entries     int            `json:"entries"`  // reported entries that were compared
+	Worklogs    int            `json:"worklogs"` // worklogs of the user found in the range
+	Drift       []WorklogDrift `json:"drift"`
+}
+
+type WorklogImportResult struct {
+	DryRun          bool        `json:"dry_run"`
+	Worklogs        int         `json:"worklogs"`         // worklogs of the user found in the range
+	Imported        int         `json:"imported"`         // worklogs that are (or would be) imported
+	Skipped         int         `json:"skipped"`          // worklogs that were imported before
+	ProjectsCreated []string    `json:"projects_created"` // names of the projects that are (or would be) created
+	Entries         []TimeEntry `json:"entries"`          // the imported entries
+	Errors          []string    `json:"errors,omitempty"` // worklogs that could not be imported and why
+}
+
+type DriftResolutionResult struct {
+	Kind        string     `json:"kind"`
+	Action      string     `json:"action"`
+	TimeEntryID string     `json:"time_entry_id,omitempty"`
+	WorklogID   string     `json:"worklog_id,omitempty"`
+	TimeEntry   *TimeEntry `json:"time_entry,omitempty"` // the entry after the resolution, unless it was deleted
+	Error       string     `json:"error,omitempty"`
+}
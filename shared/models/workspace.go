@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Roles of workspace members, from most to least access. Owners and admins
+// manage the workspace, managers also see the time of other members and members
+// log time against the projects.
+const (
+	RoleOwner   = "owner"
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleMember  = "member"
+)
+
+// Workspace is a team that shares projects. Every member can log time against
+// the projects of the workspace, while time entries stay owned by the member.
+type Workspace struct {
+	ID        string            `bson:"_id" json:"id"`
+	Name      string            `bson:"name" json:"name"`
+	OwnerID   string            `bson:"owner_id" json:"owner_id"`
+	Members   []WorkspaceMember `bson:"members" json:"members"`
+	CreatedAt time.Time         `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time         `bson:"updated_at" json:"updated_at"`
+	DeletedAt *time.Time        `bson:"deleted_at,omitempty" json:"-"`
+}
+
+type WorkspaceMember struct {
+	UserID   string    `bson:"user_id" json:"user_id"`
+	Email    string    `bson:"email" json:"email"`
+	Role     string    `bson:"role" json:"role"`
+	JoinedAt time.Time `bson:"joined_at" json:"joined_at"`
+}